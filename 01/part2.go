@@ -1,95 +1,361 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	fileutils "kqarryzada/advent-of-code-2023/utils"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 )
 
-// hasSubstringAtIndex safely checks whether the requested string is present as
-// a substring starting at the provided index. For example, for an input of
-// ("oneString", 0, "one"), this function will return true.
-func hasSubstringAtIndex(line string, i int, number string) bool {
-	// Check that there are enough characters remaining in the string.
-	if len(line)-i < len(number) {
-		return false
+// The number words recognized by default, in addition to the digits.
+var DEFAULT_WORDS = map[string]int{
+	"one":   1,
+	"two":   2,
+	"three": 3,
+	"four":  4,
+	"five":  5,
+	"six":   6,
+	"seven": 7,
+	"eight": 8,
+	"nine":  9,
+}
+
+// A wordMatch is a vocabulary word that was found while scanning a line.
+type wordMatch struct {
+	word  string
+	value int
+}
+
+// trieNode is a state of the automaton. Each state corresponds to a prefix of
+// one or more vocabulary words.
+type trieNode struct {
+	children map[byte]*trieNode
+
+	// The state for the longest proper suffix of this prefix that is also a
+	// prefix of some word. This is followed when no child matches the next
+	// character.
+	fail *trieNode
+
+	// The words that end at this state, including those reachable through the
+	// fail links, so that overlapping words such as "oneight" are all found.
+	outputs []wordMatch
+}
+
+// automaton is an Aho-Corasick automaton, which finds every vocabulary word in
+// a line with a single pass over its characters.
+type automaton struct {
+	root      *trieNode
+	maxLength int
+}
+
+// newAutomaton builds an automaton from a vocabulary that maps each word to its
+// numerical value.
+func newAutomaton(vocabulary map[string]int) *automaton {
+	a := &automaton{root: &trieNode{children: make(map[byte]*trieNode)}}
+
+	for word, value := range vocabulary {
+		current := a.root
+		for i := 0; i < len(word); i++ {
+			next, ok := current.children[word[i]]
+			if !ok {
+				next = &trieNode{children: make(map[byte]*trieNode)}
+				current.children[word[i]] = next
+			}
+			current = next
+		}
+		current.outputs = append(current.outputs, wordMatch{word, value})
+		a.maxLength = max(a.maxLength, len(word))
+	}
+
+	// Compute the fail links in breadth-first order, so that the fail link of
+	// a parent is always known before its children are processed.
+	queue := make([]*trieNode, 0)
+	for _, child := range a.root.children {
+		child.fail = a.root
+		queue = append(queue, child)
 	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
 
-	for j := 0; j < len(number); {
-		if line[i] != number[j] {
-			return false
+		for char, child := range current.children {
+			fail := current.fail
+			for fail != a.root && fail.children[char] == nil {
+				fail = fail.fail
+			}
+			if next, ok := fail.children[char]; ok && next != child {
+				child.fail = next
+			} else {
+				child.fail = a.root
+			}
+
+			child.outputs = append(child.outputs, child.fail.outputs...)
+			queue = append(queue, child)
 		}
+	}
 
-		i++
-		j++
+	return a
+}
+
+// step advances the automaton from the current state by a single character.
+func (a *automaton) step(current *trieNode, char byte) *trieNode {
+	for current != a.root && current.children[char] == nil {
+		current = current.fail
+	}
+	if next, ok := current.children[char]; ok {
+		return next
 	}
 
-	return true
+	return a.root
 }
 
-// extractCalibratedValue takes the first and last numerical values in a string
-// and returns a two-digit number. For example, "1nineight" returns "18".
-func extractCalibratedValue(line string) (calibratedValue int) {
-	numberArray := make([]int, 0)
+// scan feeds each character of the line through the automaton and calls
+// 'visit' with the index of the first character of every match.
+func (a *automaton) scan(line string, visit func(start int, match wordMatch)) {
+	current := a.root
+	for i := 0; i < len(line); i++ {
+		current = a.step(current, line[i])
+		for _, match := range current.outputs {
+			visit(i-len(match.word)+1, match)
+		}
+	}
+}
+
+// findFirst returns the match that starts earliest in the line, and its
+// position. Since a match is only reported once its last character is read,
+// scanning continues until no word could start before the best match found so
+// far. If there are no matches, the returned position is -1.
+func (a *automaton) findFirst(line string) (int, wordMatch) {
+	bestStart := -1
+	var best wordMatch
 
+	current := a.root
 	for i := 0; i < len(line); i++ {
-		char := line[i]
+		// Any match ending at 'i' starts no earlier than this position.
+		if bestStart != -1 && i-a.maxLength+1 >= bestStart {
+			break
+		}
+
+		current = a.step(current, line[i])
+		for _, match := range current.outputs {
+			start := i - len(match.word) + 1
+			if bestStart == -1 || start < bestStart {
+				bestStart = start
+				best = match
+			}
+		}
+	}
+
+	return bestStart, best
+}
+
+// reverse returns the string with its bytes in reverse order.
+func reverse(value string) string {
+	reversed := []byte(value)
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+
+	return string(reversed)
+}
+
+// scanner finds the first and last numbers of a line. The last number is found
+// by scanning the reversed line with an automaton of the reversed words, which
+// avoids reading the whole line from the left.
+type scanner struct {
+	forward  *automaton
+	backward *automaton
+}
+
+// newScanner creates a scanner for the digits and the provided number words.
+func newScanner(words map[string]int) *scanner {
+	vocabulary := make(map[string]int)
+	for digit := 0; digit <= 9; digit++ {
+		vocabulary[strconv.Itoa(digit)] = digit
+	}
+	for word, value := range words {
+		vocabulary[word] = value
+	}
+
+	reversedVocabulary := make(map[string]int)
+	for word, value := range vocabulary {
+		reversedVocabulary[reverse(word)] = value
+	}
+
+	return &scanner{
+		forward:  newAutomaton(vocabulary),
+		backward: newAutomaton(reversedVocabulary),
+	}
+}
+
+// A token is a number that was found within a line, along with the index of its
+// first character.
+type token struct {
+	start int
+	wordMatch
+}
+
+// String describes the token and its position, such as "one@3".
+func (t token) String() string {
+	return fmt.Sprintf("%s@%d", t.word, t.start)
+}
+
+// findEnds returns the first and last numbers of a line. The boolean is false
+// if the line does not contain any numbers.
+func (s *scanner) findEnds(line string) (first token, last token, found bool) {
+	firstIndex, firstMatch := s.forward.findFirst(line)
+	if firstIndex == -1 {
+		return first, last, false
+	}
+	reversedIndex, lastMatch := s.backward.findFirst(reverse(line))
+
+	// The last match was found in the reversed line, so its word and position
+	// are converted back to those of the original line.
+	lastMatch.word = reverse(lastMatch.word)
+	lastIndex := len(line) - reversedIndex - len(lastMatch.word)
+
+	return token{firstIndex, firstMatch}, token{lastIndex, lastMatch}, true
+}
+
+// findAll returns every number in the line, including those that overlap, in
+// the order of their positions.
+func (s *scanner) findAll(line string) []token {
+	tokens := make([]token, 0)
+	s.forward.scan(line, func(start int, match wordMatch) {
+		tokens = append(tokens, token{start, match})
+	})
+
+	// Matches are reported when their last character is read, so a longer word
+	// may be reported after a shorter word that starts later.
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return tokens[i].start < tokens[j].start
+	})
+
+	return tokens
+}
+
+// combineValues writes the digits of 'last' after the digits of 'first'. For
+// example, 1 and 8 combine to 18, while 10 and 3 combine to 103. Both values
+// must be non-negative.
+func combineValues(first int, last int) int {
+	shift := 10
+	for last >= shift {
+		shift *= 10
+	}
+
+	return first*shift + last
+}
+
+// extractCalibratedValue takes the first and last numerical values in a string
+// and combines their digits. For example, "1nineight" returns 18, and "3ten"
+// returns 310 if "ten" is a number word. If the line contains no numbers, this
+// function returns 0.
+func (s *scanner) extractCalibratedValue(line string) (calibratedValue int) {
+	first, last, found := s.findEnds(line)
+	if !found {
+		return 0
+	}
+
+	return combineValues(first.value, last.value)
+}
+
+// auditLine prints the diagnostics for a single line of the input: every
+// number that was found, the first and last numbers that were chosen, and the
+// values that part 1 and part 2 of the puzzle obtain from the line. Part 1 only
+// considers the digits of the line, so 'digitScanner' must not contain any
+// words.
+func auditLine(writer io.Writer, lineNumber int, line string, wordScanner *scanner, digitScanner *scanner) {
+	fmt.Fprintf(writer, "Line %d: %s\n", lineNumber, line)
+
+	tokens := wordScanner.findAll(line)
+	tokenNames := make([]string, len(tokens))
+	for i, t := range tokens {
+		tokenNames[i] = t.String()
+	}
+	if len(tokenNames) == 0 {
+		tokenNames = append(tokenNames, "-")
+	}
+	fmt.Fprintf(writer, "  Tokens: %s\n", strings.Join(tokenNames, " "))
+
+	if first, last, found := wordScanner.findEnds(line); found {
+		fmt.Fprintf(writer, "  First: %s (%d), Last: %s (%d)\n", first, first.value, last, last.value)
+	}
+
+	part1Value := digitScanner.extractCalibratedValue(line)
+	part2Value := wordScanner.extractCalibratedValue(line)
+	fmt.Fprintf(writer, "  Part 1: %d, Part 2: %d\n", part1Value, part2Value)
+
+	if len(tokens) == 0 {
+		fmt.Fprintln(writer, "  Warning: the line has no digits or number words, so its value is 0.")
+	} else if _, _, found := digitScanner.findEnds(line); !found {
+		fmt.Fprintln(writer, "  Warning: the line has no digits, so its part 1 value is 0.")
+	}
+	if part1Value != part2Value {
+		fmt.Fprintln(writer, "  Note: the part 1 and part 2 values differ.")
+	}
+}
 
-		// If the character is a number, add it to the array.
-		if char >= '0' && char <= '9' {
-			value := int(char) - '0'
-			numberArray = append(numberArray, value)
+// loadWords reads a vocabulary of number words from a file. Each line takes
+// the form of "word=value", such as "zero=0" or "ten=10". Values may have more
+// than one digit, but cannot be negative.
+func loadWords(filename string) (map[string]int, error) {
+	words := make(map[string]int)
+	for lineNumber, line := range fileutils.LoadFile(filename) {
+		line = strings.TrimSpace(line)
+		if line == "" {
 			continue
 		}
 
-		// If the index points to a number that's been written out, add it to
-		// the array.
-		if hasSubstringAtIndex(line, i, "one") {
-			numberArray = append(numberArray, 1)
-		} else if hasSubstringAtIndex(line, i, "two") {
-			numberArray = append(numberArray, 2)
-		} else if hasSubstringAtIndex(line, i, "three") {
-			numberArray = append(numberArray, 3)
-		} else if hasSubstringAtIndex(line, i, "four") {
-			numberArray = append(numberArray, 4)
-		} else if hasSubstringAtIndex(line, i, "five") {
-			numberArray = append(numberArray, 5)
-		} else if hasSubstringAtIndex(line, i, "six") {
-			numberArray = append(numberArray, 6)
-		} else if hasSubstringAtIndex(line, i, "seven") {
-			numberArray = append(numberArray, 7)
-		} else if hasSubstringAtIndex(line, i, "eight") {
-			numberArray = append(numberArray, 8)
-		} else if hasSubstringAtIndex(line, i, "nine") {
-			numberArray = append(numberArray, 9)
+		word, valueString, found := strings.Cut(line, "=")
+		word = strings.TrimSpace(word)
+		if !found || word == "" {
+			return nil, fmt.Errorf("%s, line %d: expected a value of the form 'word=value'", filename, lineNumber+1)
 		}
-	}
 
-	// Fetch the first and final values in the array.
-	var first int
-	var last int
-	if len(numberArray) == 0 {
-		first = 0
-		last = 0
-	} else if len(numberArray) == 1 {
-		first = numberArray[0]
-		last = numberArray[0]
-	} else {
-		first = numberArray[0]
-		last = numberArray[len(numberArray)-1]
+		value, err := strconv.Atoi(strings.TrimSpace(valueString))
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("%s, line %d: invalid value '%s'", filename, lineNumber+1, valueString)
+		}
+		words[word] = value
 	}
 
-	// Return the numbers as a two-digit number.
-	return first*10 + last
+	return words, nil
 }
 
 func main() {
-	sum := 0
+	wordsFile := flag.String("words", "", "Load the number words from a file of 'word=value' lines instead of the English words.")
+	audit := flag.Bool("audit", false, "Print the numbers found in each line, and compare the values of part 1 and part 2.")
+	flag.Parse()
 
+	words := DEFAULT_WORDS
+	if *wordsFile != "" {
+		var err error
+		words, err = loadWords(*wordsFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	numberScanner := newScanner(words)
+	digitScanner := newScanner(nil)
+
+	sum := 0
 	fileLines := fileutils.LoadFile("input.txt")
-	for _, line := range fileLines {
-		value := extractCalibratedValue(line)
+	for i, line := range fileLines {
+		if *audit {
+			auditLine(os.Stdout, i+1, line, numberScanner, digitScanner)
+		}
+
+		value := numberScanner.extractCalibratedValue(line)
 		sum += value
 	}
 
+	if *audit {
+		fmt.Println()
+	}
 	fmt.Printf("The total sum of the calibrated values is %d.\n", sum)
 }
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func Test_findAll(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []token
+	}{
+		{"Overlapping words", "oneight", []token{{0, wordMatch{"one", 1}}, {2, wordMatch{"eight", 8}}}},
+		{"Chained overlaps", "twoneight", []token{
+			{0, wordMatch{"two", 2}},
+			{2, wordMatch{"one", 1}},
+			{4, wordMatch{"eight", 8}},
+		}},
+		{"Digits and words", "a1seven2", []token{
+			{1, wordMatch{"1", 1}},
+			{2, wordMatch{"seven", 7}},
+			{7, wordMatch{"2", 2}},
+		}},
+		{"No numbers", "xyz", []token{}},
+	}
+	s := newScanner(DEFAULT_WORDS)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.findAll(tt.line); !slices.Equal(got, tt.want) {
+				t.Errorf("findAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findEnds(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantFirst token
+		wantLast  token
+		wantFound bool
+	}{
+		{"Example 1", "two1nine", token{0, wordMatch{"two", 2}}, token{4, wordMatch{"nine", 9}}, true},
+		{"Overlap at the end", "zoneight", token{1, wordMatch{"one", 1}}, token{3, wordMatch{"eight", 8}}, true},
+		{"Single digit", "ab7cd", token{2, wordMatch{"7", 7}}, token{2, wordMatch{"7", 7}}, true},
+		{"Last word before digits end", "4nineeightseven2", token{0, wordMatch{"4", 4}}, token{15, wordMatch{"2", 2}}, true},
+		{"No numbers", "abc", token{}, token{}, false},
+	}
+	s := newScanner(DEFAULT_WORDS)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last, found := s.findEnds(tt.line)
+			if found != tt.wantFound || first != tt.wantFirst || last != tt.wantLast {
+				t.Errorf("findEnds() = (%v, %v, %v), want (%v, %v, %v)",
+					first, last, found, tt.wantFirst, tt.wantLast, tt.wantFound)
+			}
+		})
+	}
+}
+
+func Test_extractCalibratedValue(t *testing.T) {
+	custom := map[string]int{"ten": 10, "one": 1, "eight": 8, "uno": 1}
+	tests := []struct {
+		name  string
+		words map[string]int
+		line  string
+		want  int
+	}{
+		{"Default words", DEFAULT_WORDS, "xtwone3four", 24},
+		{"Overlapping words", DEFAULT_WORDS, "oneight", 18},
+		{"Digits only", nil, "oneight", 0},
+		{"Multi-digit word", custom, "xxtenxx", 1010},
+		{"Multi-digit last", custom, "3ten", 310},
+		{"Multi-digit first", custom, "tenoneight", 108},
+		{"Other language", custom, "xunox", 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newScanner(tt.words).extractCalibratedValue(tt.line); got != tt.want {
+				t.Errorf("extractCalibratedValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_loadWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]int
+		wantErr bool
+	}{
+		{"Valid words", "zero=0\n ten = 10 \n\n", map[string]int{"zero": 0, "ten": 10}, false},
+		{"Missing separator", "ten\n", nil, true},
+		{"Negative value", "minus=-1\n", nil, true},
+		{"Invalid value", "ten=X\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "words.txt")
+			if err := os.WriteFile(filename, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := loadWords(filename)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadWords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(got) != len(tt.want) {
+				t.Errorf("loadWords() = %v, want %v", got, tt.want)
+			}
+			for word, value := range tt.want {
+				if got[word] != value {
+					t.Errorf("loadWords()[%s] = %v, want %v", word, got[word], value)
+				}
+			}
+		})
+	}
+}
+
+func Test_auditLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"No digits or words", "abc", "Line 1: abc\n" +
+			"  Tokens: -\n" +
+			"  Part 1: 0, Part 2: 0\n" +
+			"  Warning: the line has no digits or number words, so its value is 0.\n"},
+		{"Words but no digits", "oneight", "Line 1: oneight\n" +
+			"  Tokens: one@0 eight@2\n" +
+			"  First: one@0 (1), Last: eight@2 (8)\n" +
+			"  Part 1: 0, Part 2: 18\n" +
+			"  Warning: the line has no digits, so its part 1 value is 0.\n" +
+			"  Note: the part 1 and part 2 values differ.\n"},
+		{"Single digit", "a1b", "Line 1: a1b\n" +
+			"  Tokens: 1@1\n" +
+			"  First: 1@1 (1), Last: 1@1 (1)\n" +
+			"  Part 1: 11, Part 2: 11\n"},
+	}
+	wordScanner := newScanner(DEFAULT_WORDS)
+	digitScanner := newScanner(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			auditLine(&buffer, 1, tt.line, wordScanner, digitScanner)
+			if got := buffer.String(); got != tt.want {
+				t.Errorf("auditLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,81 +1,432 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	fileutils "kqarryzada/advent-of-code-2023/utils"
 	"log"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
 
-type gameSet struct {
-	red   int
-	green int
-	blue  int
+// A gameSet holds the number of cubes of each color that were revealed at once.
+// Any color may appear in a game record.
+type gameSet map[string]int
+
+// The colors that may appear in a game record by default.
+var DEFAULT_COLORS = "red,green,blue"
+
+// tokenType is the kind of a token in a game record.
+type tokenType int
+
+const (
+	WORD tokenType = iota
+	NUMBER
+	COLON
+	COMMA
+	SEMICOLON
+	SPACE
+	END
+)
+
+var tokenNames = map[tokenType]string{
+	WORD:      "a word",
+	NUMBER:    "a number",
+	COLON:     "':'",
+	COMMA:     "','",
+	SEMICOLON: "';'",
+	SPACE:     "a single space",
+	END:       "the end of the line",
+}
+
+// A token is a single element of a game record. The column is the 1-based
+// position of the token's first character within the line.
+type token struct {
+	kind   tokenType
+	text   string
+	column int
+}
+
+// A parseError describes an invalid game record and where the problem occurred.
+type parseError struct {
+	line    int
+	column  int
+	message string
+}
+
+func (e *parseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.line, e.column, e.message)
+}
+
+// tokenize splits a game record into tokens. Every character must belong to a
+// token, so unexpected characters are reported as errors.
+func tokenize(line string, lineNumber int) ([]token, error) {
+	tokens := make([]token, 0)
+	for i := 0; i < len(line); {
+		start := i
+		char := line[i]
+
+		var kind tokenType
+		switch {
+		case char >= '0' && char <= '9':
+			kind = NUMBER
+			for i < len(line) && line[i] >= '0' && line[i] <= '9' {
+				i++
+			}
+		case (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z'):
+			kind = WORD
+			for i < len(line) && ((line[i] >= 'a' && line[i] <= 'z') || (line[i] >= 'A' && line[i] <= 'Z')) {
+				i++
+			}
+		case char == ':':
+			kind = COLON
+			i++
+		case char == ',':
+			kind = COMMA
+			i++
+		case char == ';':
+			kind = SEMICOLON
+			i++
+		case char == ' ':
+			kind = SPACE
+			i++
+		default:
+			return nil, &parseError{lineNumber, start + 1, fmt.Sprintf("unexpected character %q", char)}
+		}
+
+		tokens = append(tokens, token{kind, line[start:i], start + 1})
+	}
+
+	tokens = append(tokens, token{END, "", len(line) + 1})
+	return tokens, nil
 }
 
-// parseGameData parses the string representation of a game history record into
-// a list of gameSet objects. An example history record can take the form of:
+// gameParser reads the tokens of a single game record, which must follow the
+// grammar:
 //
-// Game 1: 3 blue, 4 red; 1 red, 2 green, 6 blue; 2 green
-func parseGameData(gameData string) []gameSet {
+//	record = "Game" SPACE id ":" SPACE set { ";" SPACE set }
+//	set    = cubes { "," SPACE cubes }
+//	cubes  = count SPACE color
+type gameParser struct {
+	tokens     []token
+	position   int
+	lineNumber int
+
+	// The colors that may appear in the record. When empty, any word is
+	// accepted as a color.
+	colors map[string]bool
+}
+
+// errorAt creates an error that points to the provided token.
+func (p *gameParser) errorAt(t token, format string, args ...any) error {
+	return &parseError{p.lineNumber, t.column, fmt.Sprintf(format, args...)}
+}
+
+// expect consumes the next token, which must be of the provided kind.
+func (p *gameParser) expect(kind tokenType) (token, error) {
+	t := p.tokens[p.position]
+	if t.kind != kind {
+		found := tokenNames[t.kind]
+		if t.kind != END {
+			found = fmt.Sprintf("'%s'", t.text)
+		}
+		return t, p.errorAt(t, "expected %s, but found %s", tokenNames[kind], found)
+	}
+
+	p.position++
+	return t, nil
+}
+
+// parseNumber consumes a NUMBER token and returns its value.
+func (p *gameParser) parseNumber() (int, token, error) {
+	t, err := p.expect(NUMBER)
+	if err != nil {
+		return 0, t, err
+	}
+
+	value, err := strconv.Atoi(t.text)
+	if err != nil {
+		return 0, t, p.errorAt(t, "the number '%s' is too large", t.text)
+	}
+
+	return value, t, nil
+}
+
+// parseSet consumes a set of cube counts, such as "3 blue, 4 red".
+func (p *gameParser) parseSet() (gameSet, error) {
+	record := make(gameSet)
+	for {
+		count, _, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(SPACE); err != nil {
+			return nil, err
+		}
+
+		colorToken, err := p.expect(WORD)
+		if err != nil {
+			return nil, err
+		}
+		color := colorToken.text
+		if len(p.colors) != 0 && !p.colors[color] {
+			return nil, p.errorAt(colorToken, "unknown color '%s'", color)
+		}
+		if _, ok := record[color]; ok {
+			return nil, p.errorAt(colorToken, "the color '%s' appears more than once in the same set", color)
+		}
+		record[color] = count
+
+		if p.tokens[p.position].kind != COMMA {
+			return record, nil
+		}
+		p.position++
+		if _, err := p.expect(SPACE); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseRecord consumes an entire game record and returns its ID and sets.
+func (p *gameParser) parseRecord() (int, []gameSet, error) {
+	gameToken, err := p.expect(WORD)
+	if err != nil {
+		return 0, nil, err
+	}
+	if gameToken.text != "Game" {
+		return 0, nil, p.errorAt(gameToken, "expected 'Game', but found '%s'", gameToken.text)
+	}
+	if _, err := p.expect(SPACE); err != nil {
+		return 0, nil, err
+	}
+
+	id, _, err := p.parseNumber()
+	if err != nil {
+		return 0, nil, err
+	}
+	if _, err := p.expect(COLON); err != nil {
+		return 0, nil, err
+	}
+	if _, err := p.expect(SPACE); err != nil {
+		return 0, nil, err
+	}
+
 	gameList := make([]gameSet, 0)
+	for {
+		record, err := p.parseSet()
+		if err != nil {
+			return 0, nil, err
+		}
+		gameList = append(gameList, record)
+
+		if p.tokens[p.position].kind != SEMICOLON {
+			break
+		}
+		p.position++
+		if _, err := p.expect(SPACE); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if _, err := p.expect(END); err != nil {
+		return 0, nil, err
+	}
 
-	// Obtain the string data after "Game n: ".
-	dataString := strings.Split(gameData, ": ")[1]
+	return id, gameList, nil
+}
 
-	subsets := strings.Split(dataString, "; ")
-	for _, set := range subsets {
-		record := new(gameSet)
+// parseGames parses every game record of the input file. The game IDs must
+// begin at 1 and increase by one on each line. When 'colors' is not empty, only
+// the listed colors may appear in the records.
+func parseGames(fileLines []string, colors []string) ([][]gameSet, error) {
+	allowedColors := make(map[string]bool)
+	for _, color := range colors {
+		allowedColors[color] = true
+	}
 
-		colorCounts := strings.Split(set, ", ")
-		// colorCount is an individual record that takes the form of "3 blue".
-		for _, colorCount := range colorCounts {
-			countString := strings.Split(colorCount, " ")[0]
-			count, err := strconv.Atoi(countString)
-			if err != nil {
-				log.Fatal("An unexpected error occurred during parsing.", err)
-			}
+	games := make([][]gameSet, 0)
+	for i, line := range fileLines {
+		lineNumber := i + 1
+		tokens, err := tokenize(line, lineNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		p := &gameParser{tokens: tokens, lineNumber: lineNumber, colors: allowedColors}
+		id, gameList, err := p.parseRecord()
+		if err != nil {
+			return nil, err
+		}
+
+		if id != lineNumber {
+			// The ID is the third token of every valid record.
+			return nil, &parseError{lineNumber, tokens[2].column, fmt.Sprintf("expected game %d, but found game %d", lineNumber, id)}
+		}
+
+		games = append(games, gameList)
+	}
+
+	return games, nil
+}
+
+// parseColors converts a comma-separated list of colors into a slice.
+func parseColors(value string) []string {
+	colors := make([]string, 0)
+	for _, color := range strings.Split(value, ",") {
+		color = strings.TrimSpace(color)
+		if color != "" {
+			colors = append(colors, color)
+		}
+	}
 
-			if strings.Contains(colorCount, "red") {
-				record.red = count
-			} else if strings.Contains(colorCount, "green") {
-				record.green = count
-			} else if strings.Contains(colorCount, "blue") {
-				record.blue = count
-			} else {
-				log.Fatal("The game record did not contain an expected color.", gameData)
+	return colors
+}
+
+// parseLimits converts a list of "color=count" values, separated by commas or
+// newlines, into the maximum number of cubes of each color in the bag.
+func parseLimits(value string) (gameSet, error) {
+	limits := make(gameSet)
+	entries := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == '\n'
+	})
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		color, countString, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("expected a value of the form 'color=count', but found '%s'", entry)
+		}
+
+		count, err := strconv.Atoi(strings.TrimSpace(countString))
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid cube count in '%s'", entry)
+		}
+		limits[strings.TrimSpace(color)] = count
+	}
+
+	return limits, nil
+}
+
+// loadLimits returns the bag contents provided by the -limits value, or by the
+// file named by -limits-file if it is set. A nil result means that no bag
+// contents were provided.
+func loadLimits(value string, filename string) (gameSet, error) {
+	if filename != "" {
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		value = string(b)
+	}
+	if value == "" {
+		return nil, nil
+	}
+
+	return parseLimits(value)
+}
+
+// isPossible returns true if every set of cubes fits within the limits. Colors
+// that are not listed in the limits are not present in the bag.
+func isPossible(gameDataList []gameSet, limits gameSet) bool {
+	for _, record := range gameDataList {
+		for color, count := range record {
+			if count > limits[color] {
+				return false
 			}
 		}
+	}
 
-		gameList = append(gameList, *record)
+	return true
+}
+
+// minimumBag computes the maximum number of cubes of each color seen throughout
+// all of the sets of a game. In other words, this is the minimum number of cubes
+// of each color that must exist for the game to be possible. Every color in
+// 'colors' is included, even if it never appeared in the game.
+func minimumBag(gameDataList []gameSet, colors []string) gameSet {
+	maximums := make(gameSet)
+	for _, color := range colors {
+		maximums[color] = 0
 	}
 
-	return gameList
+	for _, record := range gameDataList {
+		for color, count := range record {
+			maximums[color] = max(count, maximums[color])
+		}
+	}
+
+	return maximums
 }
 
 func main() {
-	sum := 0
+	colorsFlag := flag.String("colors", DEFAULT_COLORS, "The colors that may appear in the game records, separated by commas. Use an empty value to accept any color.")
+	limitsFlag := flag.String("limits", "", "Sum the IDs of the games that are possible with these cubes in the bag, e.g. 'red=12, green=13, blue=14' (part 1).")
+	limitsFile := flag.String("limits-file", "", "Like -limits, but load the bag contents from a file containing one 'color=count' value per line.")
+	flag.Parse()
+
+	// This object represents the maximum constraints for the game. It is only
+	// used when the bag contents are provided.
+	maximum, err := loadLimits(*limitsFlag, *limitsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	colors := parseColors(*colorsFlag)
+	if maximum != nil {
+		// The colors in the bag are always valid, even if they are not listed.
+		if len(colors) != 0 {
+			for color := range maximum {
+				colors = append(colors, color)
+			}
+		}
+	}
 
 	fileLines := fileutils.LoadFile("input.txt")
-	for _, line := range fileLines {
-		gameDataList := parseGameData(line)
+	games, err := parseGames(fileLines, colors)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if maximum != nil {
+		sum := 0
+		for i, gameDataList := range games {
+			gameNumber := i + 1
+			if isPossible(gameDataList, maximum) {
+				sum += gameNumber
+			}
+		}
 
-		// This gameSet contains the maximum values of each color seen
-		// throughout all of the games. In other words, this is the minimum
-		// number of cubes of each color that must exist for the game to be
-		// possible. Each color will automatically be initialized to zero.
-		maximums := new(gameSet)
+		fmt.Printf("The total sum of the possible game numbers is %d.\n", sum)
+		return
+	}
 
+	// Gather every color that appears in the input, so that a game which never
+	// reveals one of the colors has a power of zero.
+	colors = make([]string, 0)
+	for _, gameDataList := range games {
 		for _, record := range gameDataList {
-			maximums.red = max(record.red, maximums.red)
-			maximums.green = max(record.green, maximums.green)
-			maximums.blue = max(record.blue, maximums.blue)
+			for color := range record {
+				if !slices.Contains(colors, color) {
+					colors = append(colors, color)
+				}
+			}
 		}
+	}
 
-		// The "power" requested by the problem is the product of the three
-		// maximum values.
-		power := maximums.red * maximums.green * maximums.blue
+	sum := 0
+	for _, gameDataList := range games {
+		// The "power" requested by the problem is the product of the maximum
+		// values of every color.
+		power := 1
+		for _, count := range minimumBag(gameDataList, colors) {
+			power *= count
+		}
 
 		sum += power
 	}
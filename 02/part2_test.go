@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	fileutils "kqarryzada/advent-of-code-2023/utils"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_parseGames(t *testing.T) {
+	tests := []struct {
+		name       string
+		lines      []string
+		colors     []string
+		wantLine   int
+		wantColumn int
+		wantText   string
+	}{
+		{"Extra space after the colon", []string{"Game 1:  3 blue"}, nil, 1, 9, "expected a number"},
+		{"Extra space between count and color", []string{"Game 1: 3  blue"}, nil, 1, 11, "expected a word"},
+		{"Trailing space", []string{"Game 1: 3 blue "}, nil, 1, 15, "expected the end of the line"},
+		{"Tab character", []string{"Game 1:\t3 blue"}, nil, 1, 8, "unexpected character"},
+		{"Unknown color", []string{"Game 1: 3 blue", "Game 2: 1 reddish"}, []string{"red", "green", "blue"}, 2, 11, "unknown color 'reddish'"},
+		{"Color followed by a letter and digit", []string{"Game 1: 1 red2"}, nil, 1, 14, "expected the end of the line"},
+		{"Non-sequential game ID", []string{"Game 1: 3 blue", "Game 3: 1 red"}, nil, 2, 6, "expected game 2, but found game 3"},
+		{"Game ID of zero", []string{"Game 0: 3 blue"}, nil, 1, 6, "expected game 1, but found game 0"},
+		{"Repeated color", []string{"Game 1: 3 blue, 2 blue"}, nil, 1, 19, "appears more than once"},
+		{"Missing set", []string{"Game 1: 3 blue; "}, nil, 1, 17, "expected a number"},
+		{"Wrong keyword", []string{"Round 1: 3 blue"}, nil, 1, 1, "expected 'Game'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseGames(tt.lines, tt.colors)
+
+			var parseErr *parseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("parseGames() error = %v, want a parseError", err)
+			}
+			if parseErr.line != tt.wantLine || parseErr.column != tt.wantColumn {
+				t.Errorf("parseGames() error at line %d, column %d, want line %d, column %d (%v)",
+					parseErr.line, parseErr.column, tt.wantLine, tt.wantColumn, err)
+			}
+			if !strings.Contains(parseErr.message, tt.wantText) {
+				t.Errorf("parseGames() error = %q, want it to contain %q", parseErr.message, tt.wantText)
+			}
+		})
+	}
+}
+
+func Test_parseGames_valid(t *testing.T) {
+	lines := []string{
+		"Game 1: 3 blue, 4 red; 1 red, 2 green, 6 blue; 2 green",
+		"Game 2: 1 blue, 2 green; 3 green, 4 blue, 1 red; 1 green, 1 blue",
+	}
+	games, err := parseGames(lines, parseColors(DEFAULT_COLORS))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(games) != 2 || len(games[0]) != 3 || len(games[1]) != 3 {
+		t.Fatalf("parseGames() = %v, want two games of three sets", games)
+	}
+	if set := games[0][1]; set["red"] != 1 || set["green"] != 2 || set["blue"] != 6 {
+		t.Errorf("second set of game 1 = %v, want 1 red, 2 green, and 6 blue", set)
+	}
+
+	bag := minimumBag(games[0], parseColors(DEFAULT_COLORS))
+	if bag["red"] != 4 || bag["green"] != 2 || bag["blue"] != 6 {
+		t.Errorf("minimumBag() = %v, want 4 red, 2 green, and 6 blue", bag)
+	}
+}
+
+func Test_parseLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    gameSet
+		wantErr string
+	}{
+		{"Puzzle limits", "red=12, green=13, blue=14", gameSet{"red": 12, "green": 13, "blue": 14}, ""},
+		{"One value per line", "red=12\ngreen=13\nblue=14\n", gameSet{"red": 12, "green": 13, "blue": 14}, ""},
+		{"Extra spaces", " red = 0 ,, blue=3", gameSet{"red": 0, "blue": 3}, ""},
+		{"Missing equals sign", "red=12, green 13", nil, "expected a value of the form 'color=count', but found 'green 13'"},
+		{"Count is not a number", "red=twelve", nil, "invalid cube count in 'red=twelve'"},
+		{"Negative count", "red=12, blue=-1", nil, "invalid cube count in 'blue=-1'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLimits(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseLimits() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("parseLimits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_loadLimits(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "limits.txt")
+	if err := os.WriteFile(filename, []byte("red=12\ngreen=13\nblue=14\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := gameSet{"red": 12, "green": 13, "blue": 14}
+	got, err := loadLimits("red=1", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("loadLimits() = %v, want %v", got, want)
+	}
+
+	if got, err := loadLimits("", ""); got != nil || err != nil {
+		t.Errorf("loadLimits() = %v, %v, want no limits", got, err)
+	}
+	if _, err := loadLimits("", filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("loadLimits() did not return an error for a missing file")
+	}
+}
+
+func Test_isPossible(t *testing.T) {
+	games, err := parseGames(fileutils.LoadFile("example.txt"), parseColors(DEFAULT_COLORS))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		limits  string
+		want    []int
+		wantSum int
+	}{
+		{"Puzzle limits", "red=12, green=13, blue=14", []int{1, 2, 5}, 8},
+		{"Missing color", "red=20, green=13", nil, 0},
+		{"Large bag", "red=20, green=13, blue=15", []int{1, 2, 3, 4, 5}, 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits, err := parseLimits(tt.limits)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []int
+			sum := 0
+			for i, gameDataList := range games {
+				if isPossible(gameDataList, limits) {
+					got = append(got, i+1)
+					sum += i + 1
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("possible games = %v, want %v", got, tt.want)
+			}
+			if sum != tt.wantSum {
+				t.Errorf("sum of the possible games = %d, want %d", sum, tt.wantSum)
+			}
+		})
+	}
+}
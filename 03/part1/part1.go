@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	fileutils "kqarryzada/advent-of-code-2023/utils"
+	"slices"
 )
 
 func isDigit(input rune) bool {
@@ -13,101 +14,123 @@ func isSpecialCharacter(input rune) bool {
 	return input != '.' && !isDigit(input)
 }
 
-func getValueAndOverwrite(row int, column int, matrix [][]rune) int {
-	retval := int(matrix[row][column]) - '0'
-	matrix[row][column] = '.'
-	return retval
+// A partNumber is a number in the engine schematic, along with the span of
+// columns that its digits occupy.
+type partNumber struct {
+	value    int
+	row      int
+	startCol int
+	endCol   int
 }
 
-// extractNumericalValue obtains a number from the string matrix. For example,
-// for the following matrix:
-//
-// ...
-// 4.*
-// .12
-// ..*
-//
-// If the function is provided with a row and column value of (2, 1), the number
-// "12" will be returned. The matrix will also be modified to replace the '1'
-// and '2' characters with '.' so that the value will not be doubly counted in a
-// future invocation.
-//
-// If the function is provided with a row and column value that does not point
-// to a digit (e.g., (0, 0)), the function will return 0.
-func extractNumericalValue(row int, column int, matrix [][]rune) int {
-	if !isDigit(matrix[row][column]) {
-		return 0
-	}
-
-	retval := getValueAndOverwrite(row, column, matrix)
-
-	// Iterate through values on the left and update the calculated number.
-	base := 10
-	for i := column - 1; i >= 0; i-- {
-		if !isDigit(matrix[row][i]) {
-			break
-		}
+// A symbol is a special character in the engine schematic.
+type symbol struct {
+	char rune
+	row  int
+	col  int
+}
 
-		value := getValueAndOverwrite(row, i, matrix)
-		retval += base * value
+// schematic is an index of the numbers and symbols in the engine schematic.
+// Adjacency between the numbers and symbols is stored as a relation in both
+// directions, so that a number adjacent to several symbols is related to each
+// of them.
+type schematic struct {
+	numbers []partNumber
+	symbols []symbol
+
+	// For each cell of the matrix, the index of the number that occupies it,
+	// or -1 if the cell does not contain a digit.
+	numberAt [][]int
+
+	// For each symbol, the indexes of its adjacent numbers, and for each number,
+	// the indexes of its adjacent symbols.
+	numbersBySymbol [][]int
+	symbolsByNumber [][]int
+}
 
-		base *= 10
+// parseSchematic locates every number and symbol in the matrix and computes the
+// adjacency between them. The matrix is not modified.
+func parseSchematic(matrix [][]rune) *schematic {
+	s := &schematic{
+		numbers:  make([]partNumber, 0),
+		symbols:  make([]symbol, 0),
+		numberAt: make([][]int, len(matrix)),
 	}
 
-	lastIndex := len(matrix[0]) - 1
-	for i := column + 1; i <= lastIndex; i++ {
-		if !isDigit(matrix[row][i]) {
-			break
+	for row, line := range matrix {
+		s.numberAt[row] = make([]int, len(line))
+		for col := 0; col < len(line); col++ {
+			s.numberAt[row][col] = -1
+			char := line[col]
+
+			if isSpecialCharacter(char) {
+				s.symbols = append(s.symbols, symbol{char, row, col})
+				continue
+			}
+			if !isDigit(char) {
+				continue
+			}
+
+			// Consume every digit of the number so that its span is recorded.
+			number := partNumber{row: row, startCol: col}
+			for ; col < len(line) && isDigit(line[col]); col++ {
+				number.value = (number.value * 10) + int(line[col]-'0')
+				s.numberAt[row][col] = len(s.numbers)
+			}
+			number.endCol = col - 1
+			s.numbers = append(s.numbers, number)
+
+			// Revisit the character that ended the number.
+			col--
 		}
+	}
 
-		value := getValueAndOverwrite(row, i, matrix)
-		retval = (retval * 10) + value
+	s.numbersBySymbol = make([][]int, len(s.symbols))
+	s.symbolsByNumber = make([][]int, len(s.numbers))
+	for i, sym := range s.symbols {
+		for _, numberIndex := range s.adjacentNumbers(sym.row, sym.col) {
+			s.numbersBySymbol[i] = append(s.numbersBySymbol[i], numberIndex)
+			s.symbolsByNumber[numberIndex] = append(s.symbolsByNumber[numberIndex], i)
+		}
 	}
 
-	return retval
+	return s
 }
 
-// calculateLocalSum takes in the coordinates of a spceial character and fetches
-// the sum of its neighboring numbers in the matrix, if any exist. The contents
-// of the matrix will be updated so that a number cannot be included in the
-// overall sum twice.
-func calculateLocalSum(row int, column int, matrix [][]rune) int {
-	localSum := 0
-	lastColumnIndex := len(matrix[0]) - 1
-	lastRowIndex := len(matrix) - 1
-
-	// In most cases, the local sum is calculated by starting at
-	// [row - 1, column -1] in the matrix and checking for digits from left to
-	// right, with nine checks in total. The values of i and j are bounded so
-	// that they never exceed the boundaries of the matrix.
+// adjacentNumbers returns the indexes of the distinct numbers that occupy any
+// of the nine cells centered on the provided coordinates. The values of the
+// rows and columns are bounded so that they never exceed the boundaries of the
+// matrix.
+func (s *schematic) adjacentNumbers(row int, column int) []int {
+	indexes := make([]int, 0)
+
 	startingRow := max(row-1, 0)
-	endingRow := min(row+1, lastRowIndex)
-	startingColumn := max(column-1, 0)
-	endingColumn := min(column+1, lastColumnIndex)
+	endingRow := min(row+1, len(s.numberAt)-1)
 	for i := startingRow; i <= endingRow; i++ {
+		startingColumn := max(column-1, 0)
+		endingColumn := min(column+1, len(s.numberAt[i])-1)
 		for j := startingColumn; j <= endingColumn; j++ {
-			localSum += extractNumericalValue(i, j, matrix)
-		}
-	}
-
-	return localSum
-}
-
-func processRow(rowNumber int, matrix [][]rune) int {
-	rowSum := 0
-	for i, char := range matrix[rowNumber] {
-		if isSpecialCharacter(char) {
-			rowSum += calculateLocalSum(rowNumber, i, matrix)
+			numberIndex := s.numberAt[i][j]
+			if numberIndex != -1 && !slices.Contains(indexes, numberIndex) {
+				indexes = append(indexes, numberIndex)
+			}
 		}
 	}
 
-	return rowSum
+	return indexes
 }
 
+// processMatrix sums every number that is adjacent to at least one special
+// character. A number adjacent to several special characters is only counted
+// once.
 func processMatrix(matrix [][]rune) int {
+	s := parseSchematic(matrix)
+
 	sum := 0
-	for row := 0; row < len(matrix); row++ {
-		sum += processRow(row, matrix)
+	for i, number := range s.numbers {
+		if len(s.symbolsByNumber[i]) != 0 {
+			sum += number.value
+		}
 	}
 
 	return sum
@@ -31,7 +31,10 @@ func Test_isSpecialCharacter(t *testing.T) {
 	}
 }
 
-func Test_calculateLocalSum(t *testing.T) {
+// Test_adjacentNumbers sums the distinct numbers that are adjacent to a single
+// special character. Each neighboring number is only included once, even if
+// several of its digits are adjacent to the special character.
+func Test_adjacentNumbers(t *testing.T) {
 	type args struct {
 		row    int
 		column int
@@ -123,8 +126,38 @@ func Test_calculateLocalSum(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := calculateLocalSum(tt.args.row, tt.args.column, tt.args.matrix); got != tt.want {
-				t.Errorf("calculateLocalSum() = %v, want %v", got, tt.want)
+			s := parseSchematic(tt.args.matrix)
+
+			got := 0
+			for _, numberIndex := range s.adjacentNumbers(tt.args.row, tt.args.column) {
+				got += s.numbers[numberIndex].value
+			}
+			if got != tt.want {
+				t.Errorf("sum of adjacentNumbers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_processMatrix(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix []string
+		want   int
+	}{
+		{"Number adjacent to two symbols", []string{"*..", "12.", "..#"}, 12},
+		{"Number with no symbols", []string{"12.", "...", "..#"}, 0},
+		{"Numbers sharing a symbol", []string{"1.2", ".*.", "3.."}, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matrix := make([][]rune, len(tt.matrix))
+			for i, line := range tt.matrix {
+				matrix[i] = []rune(line)
+			}
+
+			if got := processMatrix(matrix); got != tt.want {
+				t.Errorf("processMatrix() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -1,14 +1,23 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	fileutils "kqarryzada/advent-of-code-2023/utils"
+	"log"
+	"os"
+	"slices"
 )
 
 func isDigit(input rune) bool {
 	return input >= '0' && input <= '9'
 }
 
+func isSpecialCharacter(input rune) bool {
+	return input != '.' && !isDigit(input)
+}
+
 // isGearCharacter returns true if the input is equivalent to the '*' character.
 // Note that this does not calculate whether or not there are only two
 // neighboring numbers in the matrix.
@@ -16,111 +25,285 @@ func isGearCharacter(input rune) bool {
 	return input == '*'
 }
 
-func getValueAndOverwrite(row int, column int, matrix [][]rune) int {
-	retval := int(matrix[row][column]) - '0'
-	matrix[row][column] = '.'
-	return retval
+// A partNumber is a number in the engine schematic, along with the span of
+// columns that its digits occupy.
+type partNumber struct {
+	value    int
+	row      int
+	startCol int
+	endCol   int
 }
 
-// extractNumericalValue obtains a number from the string matrix. For example,
-// for the following matrix:
-//
-// ...
-// 4.*
-// .12
-// ..*
-//
-// If the function is provided with a row and column value of (2, 1), the number
-// "12" will be returned. The matrix will also be modified to replace the '1'
-// and '2' characters with '.' so that the value will not be doubly counted in a
-// future invocation.
-//
-// If the function is provided with a row and column value that does not point
-// to a digit (e.g., (0, 0)), the function will return 0.
-func extractNumericalValue(row int, column int, matrix [][]rune) int {
-	if !isDigit(matrix[row][column]) {
-		return 0
-	}
+// A symbol is a special character in the engine schematic.
+type symbol struct {
+	char rune
+	row  int
+	col  int
+}
 
-	retval := getValueAndOverwrite(row, column, matrix)
+// schematic is an index of the numbers and symbols in the engine schematic.
+// Adjacency between the numbers and symbols is stored as a relation in both
+// directions, so that a number adjacent to several symbols is related to each
+// of them.
+type schematic struct {
+	numbers []partNumber
+	symbols []symbol
 
-	// Iterate through values on the left and update the calculated number.
-	base := 10
-	for i := column - 1; i >= 0; i-- {
-		if !isDigit(matrix[row][i]) {
-			break
-		}
+	// For each cell of the matrix, the index of the number that occupies it,
+	// or -1 if the cell does not contain a digit.
+	numberAt [][]int
 
-		value := getValueAndOverwrite(row, i, matrix)
-		retval += base * value
+	// For each symbol, the indexes of its adjacent numbers, and for each number,
+	// the indexes of its adjacent symbols.
+	numbersBySymbol [][]int
+	symbolsByNumber [][]int
+}
 
-		base *= 10
+// parseSchematic locates every number and symbol in the matrix and computes the
+// adjacency between them. The matrix is not modified.
+func parseSchematic(matrix [][]rune) *schematic {
+	s := &schematic{
+		numbers:  make([]partNumber, 0),
+		symbols:  make([]symbol, 0),
+		numberAt: make([][]int, len(matrix)),
 	}
 
-	lastIndex := len(matrix[0]) - 1
-	for i := column + 1; i <= lastIndex; i++ {
-		if !isDigit(matrix[row][i]) {
-			break
-		}
+	for row, line := range matrix {
+		s.numberAt[row] = make([]int, len(line))
+		for col := 0; col < len(line); col++ {
+			s.numberAt[row][col] = -1
+			char := line[col]
 
-		value := getValueAndOverwrite(row, i, matrix)
-		retval = (retval * 10) + value
-	}
+			if isSpecialCharacter(char) {
+				s.symbols = append(s.symbols, symbol{char, row, col})
+				continue
+			}
+			if !isDigit(char) {
+				continue
+			}
 
-	return retval
-}
+			// Consume every digit of the number so that its span is recorded.
+			number := partNumber{row: row, startCol: col}
+			for ; col < len(line) && isDigit(line[col]); col++ {
+				number.value = (number.value * 10) + int(line[col]-'0')
+				s.numberAt[row][col] = len(s.numbers)
+			}
+			number.endCol = col - 1
+			s.numbers = append(s.numbers, number)
 
-// calculateGearRatio takes in the coordinates of a character and obtains
-// the "gear ratio". A "gear" is a '*' character in the input matrix which has
-// two and only two neighboring numbers.  This function returns the product of
-// these two numbers, which is known as the "gear value". If the character at
-// the provided coordinates is not a proper gear (even if it points to a '*'
-// character), this function will return 0.
-func calculateGearRatio(row int, column int, matrix [][]rune) int {
-	if !isGearCharacter(matrix[row][column]) {
-		return 0
+			// Revisit the character that ended the number.
+			col--
+		}
 	}
 
-	lastColumnIndex := len(matrix[0]) - 1
-	lastRowIndex := len(matrix) - 1
+	s.numbersBySymbol = make([][]int, len(s.symbols))
+	s.symbolsByNumber = make([][]int, len(s.numbers))
+	for i, sym := range s.symbols {
+		for _, numberIndex := range s.adjacentNumbers(sym.row, sym.col) {
+			s.numbersBySymbol[i] = append(s.numbersBySymbol[i], numberIndex)
+			s.symbolsByNumber[numberIndex] = append(s.symbolsByNumber[numberIndex], i)
+		}
+	}
 
-	gearRatios := make([]int, 0)
+	return s
+}
+
+// adjacentNumbers returns the indexes of the distinct numbers that occupy any
+// of the nine cells centered on the provided coordinates. The values of the
+// rows and columns are bounded so that they never exceed the boundaries of the
+// matrix.
+func (s *schematic) adjacentNumbers(row int, column int) []int {
+	indexes := make([]int, 0)
 
-	// In most cases, the local sum is calculated by starting at
-	// [row - 1, column -1] in the matrix and checking for digits from left to
-	// right, with nine checks in total. The values of i and j are bounded so
-	// that they never exceed the boundaries of the matrix.
 	startingRow := max(row-1, 0)
-	endingRow := min(row+1, lastRowIndex)
-	startingColumn := max(column-1, 0)
-	endingColumn := min(column+1, lastColumnIndex)
+	endingRow := min(row+1, len(s.numberAt)-1)
 	for i := startingRow; i <= endingRow; i++ {
+		startingColumn := max(column-1, 0)
+		endingColumn := min(column+1, len(s.numberAt[i])-1)
 		for j := startingColumn; j <= endingColumn; j++ {
-			value := extractNumericalValue(i, j, matrix)
-			if value != 0 {
-				gearRatios = append(gearRatios, value)
+			numberIndex := s.numberAt[i][j]
+			if numberIndex != -1 && !slices.Contains(indexes, numberIndex) {
+				indexes = append(indexes, numberIndex)
 			}
 		}
 	}
 
+	return indexes
+}
+
+// calculateGearRatio obtains the "gear ratio" of a symbol. A "gear" is a '*'
+// character in the input matrix which has two and only two neighboring numbers.
+// This function returns the product of these two numbers, which is known as the
+// "gear value". If the symbol is not a proper gear, this function will return 0.
+func (s *schematic) calculateGearRatio(symbolIndex int) int {
+	if !isGearCharacter(s.symbols[symbolIndex].char) {
+		return 0
+	}
+
+	gearRatios := s.numbersBySymbol[symbolIndex]
 	if len(gearRatios) != 2 {
 		return 0
 	}
 
-	return gearRatios[0] * gearRatios[1]
+	return s.numbers[gearRatios[0]].value * s.numbers[gearRatios[1]].value
 }
 
 func processMatrix(matrix [][]rune) int {
+	s := parseSchematic(matrix)
+
 	sum := 0
-	for row := 0; row < len(matrix); row++ {
-		for column, _ := range matrix[row] {
-			sum += calculateGearRatio(row, column, matrix)
+	for i := range s.symbols {
+		sum += s.calculateGearRatio(i)
+	}
+
+	return sum
+}
+
+// parseSymbolFilter converts the value of the '-symbol' flag into a function
+// that selects symbols. An empty value selects every special character, while
+// a single character selects only that symbol.
+func parseSymbolFilter(value string) (func(rune) bool, error) {
+	chars := []rune(value)
+	switch {
+	case len(chars) == 0:
+		return isSpecialCharacter, nil
+	case len(chars) == 1 && isSpecialCharacter(chars[0]):
+		return func(input rune) bool { return input == chars[0] }, nil
+	default:
+		return nil, fmt.Errorf("'%s' is not a special character", value)
+	}
+}
+
+// numbersAdjacentTo returns the indexes of the distinct numbers that are
+// adjacent to at least one symbol selected by the filter.
+func (s *schematic) numbersAdjacentTo(filter func(rune) bool) []int {
+	indexes := make([]int, 0)
+	for i := range s.numbers {
+		for _, symbolIndex := range s.symbolsByNumber[i] {
+			if filter(s.symbols[symbolIndex].char) {
+				indexes = append(indexes, i)
+				break
+			}
 		}
 	}
 
+	return indexes
+}
+
+// symbolsWithNeighborCount returns the indexes of the symbols selected by the
+// filter that have exactly 'count' neighboring numbers.
+func (s *schematic) symbolsWithNeighborCount(filter func(rune) bool, count int) []int {
+	indexes := make([]int, 0)
+	for i, sym := range s.symbols {
+		if filter(sym.char) && len(s.numbersBySymbol[i]) == count {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+// orphanNumbers returns the indexes of the numbers that are not adjacent to any
+// symbol.
+func (s *schematic) orphanNumbers() []int {
+	indexes := make([]int, 0)
+	for i := range s.numbers {
+		if len(s.symbolsByNumber[i]) == 0 {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+// symbolSum computes the sum of the numbers adjacent to a symbol.
+func (s *schematic) symbolSum(symbolIndex int) int {
+	sum := 0
+	for _, numberIndex := range s.numbersBySymbol[symbolIndex] {
+		sum += s.numbers[numberIndex].value
+	}
+
 	return sum
 }
 
+// neighborValues lists the values of the numbers adjacent to a symbol.
+func (s *schematic) neighborValues(symbolIndex int) []int {
+	values := make([]int, 0)
+	for _, numberIndex := range s.numbersBySymbol[symbolIndex] {
+		values = append(values, s.numbers[numberIndex].value)
+	}
+
+	return values
+}
+
+// runQuery answers a question about the schematic. The first argument is the
+// kind of query, which is one of:
+//
+//	adjacent   lists the numbers adjacent to the selected symbols
+//	neighbors  lists the selected symbols with exactly '-count' neighbors
+//	orphans    lists the numbers that are not adjacent to any symbol
+//	sums       lists the sum of the neighbors of each selected symbol
+//
+// Rows and columns are reported starting from 1. The results are written to
+// 'writer'.
+func runQuery(writer io.Writer, args []string, s *schematic) error {
+	if len(args) == 0 {
+		return fmt.Errorf("a query is required: adjacent, neighbors, orphans, or sums")
+	}
+
+	queryFlags := flag.NewFlagSet("query "+args[0], flag.ContinueOnError)
+	symbolFlag := queryFlags.String("symbol", "", "Only consider this symbol. By default, every symbol is considered.")
+	count := queryFlags.Int("count", 2, "The number of neighbors for the 'neighbors' query.")
+	if err := queryFlags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	filter, err := parseSymbolFilter(*symbolFlag)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "adjacent":
+		total := 0
+		for _, i := range s.numbersAdjacentTo(filter) {
+			number := s.numbers[i]
+			total += number.value
+			fmt.Fprintf(writer, "%d at row %d, columns %d-%d\n", number.value, number.row+1, number.startCol+1, number.endCol+1)
+		}
+		fmt.Fprintf(writer, "Total: %d\n", total)
+
+	case "neighbors":
+		for _, i := range s.symbolsWithNeighborCount(filter, *count) {
+			sym := s.symbols[i]
+			values := s.neighborValues(i)
+			product := 1
+			for _, value := range values {
+				product *= value
+			}
+			fmt.Fprintf(writer, "%c at row %d, column %d: neighbors %v, product %d\n", sym.char, sym.row+1, sym.col+1, values, product)
+		}
+
+	case "orphans":
+		for _, i := range s.orphanNumbers() {
+			number := s.numbers[i]
+			fmt.Fprintf(writer, "%d at row %d, columns %d-%d\n", number.value, number.row+1, number.startCol+1, number.endCol+1)
+		}
+
+	case "sums":
+		for i, sym := range s.symbols {
+			if filter(sym.char) {
+				fmt.Fprintf(writer, "%c at row %d, column %d: %d\n", sym.char, sym.row+1, sym.col+1, s.symbolSum(i))
+			}
+		}
+
+	default:
+		return fmt.Errorf("unknown query '%s', use adjacent, neighbors, orphans, or sums", args[0])
+	}
+
+	return nil
+}
+
 func main() {
 	fileLines := fileutils.LoadFile("input.txt")
 	matrix := make([][]rune, 0)
@@ -129,6 +312,13 @@ func main() {
 		matrix = append(matrix, charArray)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "query" {
+		if err := runQuery(os.Stdout, os.Args[2:], parseSchematic(matrix)); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	sum := processMatrix(matrix)
 	fmt.Printf("The sum of all the gear ratios is %d.\n", sum)
 }
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	fileutils "kqarryzada/advent-of-code-2023/utils"
+	"slices"
+	"testing"
+)
+
+// toMatrix converts the lines of a schematic into a matrix of characters.
+func toMatrix(lines []string) [][]rune {
+	matrix := make([][]rune, len(lines))
+	for i, line := range lines {
+		matrix[i] = []rune(line)
+	}
+
+	return matrix
+}
+
+func Test_calculateGearRatio(t *testing.T) {
+	// The number 5 is adjacent to both gears, so it contributes to each of
+	// their gear ratios.
+	s := parseSchematic(toMatrix([]string{
+		"2.....",
+		".*5*..",
+		"....3.",
+	}))
+
+	tests := []struct {
+		name   string
+		symbol symbol
+		want   int
+	}{
+		{"First gear", symbol{'*', 1, 1}, 10},
+		{"Second gear", symbol{'*', 1, 3}, 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symbolIndex := -1
+			for i, sym := range s.symbols {
+				if sym == tt.symbol {
+					symbolIndex = i
+				}
+			}
+			if symbolIndex == -1 {
+				t.Fatalf("the symbol %v was not found", tt.symbol)
+			}
+
+			if got := s.calculateGearRatio(symbolIndex); got != tt.want {
+				t.Errorf("calculateGearRatio() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_processMatrix(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  int
+	}{
+		{"Number shared by two gears", []string{"2.....", ".*5*..", "....3."}, 25},
+		{"Gear with three numbers", []string{"1.2", ".*.", "3.."}, 0},
+		{"Gear with one number", []string{"1..", ".*.", "..."}, 0},
+		{"Two numbers next to another symbol", []string{"1.2", ".#.", "..."}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := processMatrix(toMatrix(tt.lines)); got != tt.want {
+				t.Errorf("processMatrix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// exampleSchematic indexes the example schematic from the puzzle.
+func exampleSchematic() *schematic {
+	return parseSchematic(toMatrix(fileutils.LoadFile("example.txt")))
+}
+
+func Test_queries(t *testing.T) {
+	s := exampleSchematic()
+	gears, err := parseSymbolFilter("*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allSymbols, err := parseSymbolFilter("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	numberValues := func(indexes []int) []int {
+		values := make([]int, len(indexes))
+		for i, index := range indexes {
+			values[i] = s.numbers[index].value
+		}
+		return values
+	}
+
+	t.Run("orphans", func(t *testing.T) {
+		if got, want := numberValues(s.orphanNumbers()), []int{114, 58}; !slices.Equal(got, want) {
+			t.Errorf("orphanNumbers() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("adjacent", func(t *testing.T) {
+		got := numberValues(s.numbersAdjacentTo(gears))
+		if want := []int{467, 35, 617, 755, 598}; !slices.Equal(got, want) {
+			t.Errorf("numbersAdjacentTo('*') = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("neighbors", func(t *testing.T) {
+		got := make([][]int, 0)
+		for _, i := range s.symbolsWithNeighborCount(gears, 2) {
+			got = append(got, s.neighborValues(i))
+		}
+
+		want := [][]int{{467, 35}, {755, 598}}
+		if !slices.EqualFunc(got, want, slices.Equal[[]int]) {
+			t.Errorf("symbolsWithNeighborCount('*', 2) neighbors = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("sums", func(t *testing.T) {
+		want := map[symbol]int{
+			{'*', 1, 3}: 502,
+			{'#', 3, 6}: 633,
+			{'*', 4, 3}: 617,
+			{'+', 5, 5}: 592,
+			{'$', 8, 3}: 664,
+			{'*', 8, 5}: 1353,
+		}
+		if len(s.symbols) != len(want) {
+			t.Fatalf("found %d symbols, want %d", len(s.symbols), len(want))
+		}
+		for i, sym := range s.symbols {
+			if !allSymbols(sym.char) {
+				t.Errorf("the filter for every symbol rejected %c", sym.char)
+			}
+			if got := s.symbolSum(i); got != want[sym] {
+				t.Errorf("symbolSum(%v) = %v, want %v", sym, got, want[sym])
+			}
+		}
+	})
+}
+
+func Test_parseSymbolFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		accepted []rune
+		rejected []rune
+		wantErr  bool
+	}{
+		{"Every symbol", "", []rune{'*', '#', '$'}, []rune{'.', '5'}, false},
+		{"Single symbol", "#", []rune{'#'}, []rune{'*', '$', '.'}, false},
+		{"Gear symbol", "*", []rune{'*'}, []rune{'#', '.'}, false},
+		{"Digit", "5", nil, nil, true},
+		{"Period", ".", nil, nil, true},
+		{"Several characters", "*#", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := parseSymbolFilter(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSymbolFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, char := range tt.accepted {
+				if !filter(char) {
+					t.Errorf("the filter rejected %c", char)
+				}
+			}
+			for _, char := range tt.rejected {
+				if filter(char) {
+					t.Errorf("the filter accepted %c", char)
+				}
+			}
+		})
+	}
+}
+
+func Test_runQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{"Orphans", []string{"orphans"}, "114 at row 1, columns 6-8\n58 at row 6, columns 8-9\n", false},
+		{"Neighbors", []string{"neighbors", "-symbol", "*"}, "* at row 2, column 4: neighbors [467 35], product 16345\n" +
+			"* at row 9, column 6: neighbors [755 598], product 451490\n", false},
+		{"Sums", []string{"sums", "-symbol", "$"}, "$ at row 9, column 4: 664\n", false},
+		{"Adjacent", []string{"adjacent", "-symbol", "#"}, "633 at row 3, columns 7-9\nTotal: 633\n", false},
+		{"No query", []string{}, "", true},
+		{"Unknown query", []string{"gears"}, "", true},
+		{"Invalid symbol", []string{"sums", "-symbol", "7"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			err := runQuery(&buffer, tt.args, exampleSchematic())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := buffer.String(); got != tt.want {
+				t.Errorf("runQuery() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
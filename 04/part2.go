@@ -1,66 +1,187 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	fileutils "kqarryzada/advent-of-code-2023/utils"
-	"slices"
+	"log"
+	"os"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 )
 
-// A 1-indexed slice that tracks the number of scratchcards. Index 0 is always
-// set to a value of 0.
-var scratchcards []int
+// A scratchcard holds the numbers of a single card. The numbers are stored as
+// sets, so that the matches can be counted without comparing every pair of
+// numbers.
+type scratchcard struct {
+	// The number of the card, as listed in the input file.
+	id int
 
-func processRound(line string, roundNumber int) {
-	game := strings.Split(line, ": ")[1]
-	gameData := strings.Split(game, "|")
+	cardNumbers    map[int]bool
+	winningNumbers map[int]bool
+}
+
+// parseNumbers converts a space-separated list of numbers into a set.
+func parseNumbers(value string) (map[int]bool, error) {
+	numbers := make(map[int]bool)
+	for _, field := range strings.Fields(value) {
+		number, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s'", field)
+		}
+		numbers[number] = true
+	}
+
+	return numbers, nil
+}
+
+// parseCard converts a line such as "Card 1: 41 48 83 | 83 86 6" into a
+// scratchcard.
+func parseCard(line string) (*scratchcard, error) {
+	header, game, found := strings.Cut(line, ":")
+	if !found {
+		return nil, fmt.Errorf("expected a card of the form 'Card N: ... | ...': %s", line)
+	}
 
-	cardNumbers := strings.Split(gameData[0], " ")
-	winningNumbers := strings.Split(gameData[1], " ")
+	idString, found := strings.CutPrefix(header, "Card")
+	if !found {
+		return nil, fmt.Errorf("expected the line to begin with 'Card': %s", line)
+	}
+	id, err := strconv.Atoi(strings.TrimSpace(idString))
+	if err != nil {
+		return nil, fmt.Errorf("invalid card number in: %s", line)
+	}
 
-	newScratchCards := 0
-	for _, number := range cardNumbers {
-		if len(number) != 0 && slices.Contains(winningNumbers, number) {
-			newScratchCards++
+	cardData, winningData, found := strings.Cut(game, "|")
+	if !found {
+		return nil, fmt.Errorf("expected a '|' separator in card %d", id)
+	}
+
+	card := &scratchcard{id: id}
+	if card.cardNumbers, err = parseNumbers(cardData); err != nil {
+		return nil, fmt.Errorf("card %d: %w", id, err)
+	}
+	if card.winningNumbers, err = parseNumbers(winningData); err != nil {
+		return nil, fmt.Errorf("card %d: %w", id, err)
+	}
+
+	return card, nil
+}
+
+// matchCount returns the number of the card's numbers that are winning numbers.
+func (card *scratchcard) matchCount() int {
+	count := 0
+	for number := range card.cardNumbers {
+		if card.winningNumbers[number] {
+			count++
 		}
 	}
 
-	for count := 0; count < newScratchCards; count++ {
-		i := (roundNumber + 1) + count
-		if i >= len(scratchcards) {
-			// The index has exceeded the maximum scorecard number, so there is
-			// nothing left to increment.
-			break
+	return count
+}
+
+// calculateScore returns the number of points that a card with 'winCount'
+// matches is worth.
+func calculateScore(winCount int) int {
+	if winCount == 0 {
+		return 0
+	}
+
+	// The score value is 2^(n - 1) when at least one winning number is present.
+	return 1 << (winCount - 1)
+}
+
+// cardResult is the outcome of a single scratchcard.
+type cardResult struct {
+	id      int
+	matches int
+	points  int
+
+	// The number of copies of later cards that each copy of this card wins.
+	copiesWon int
+
+	// The number of copies of this card that are held, including the original.
+	totalCopies int
+}
+
+// processCards computes the result of each card. Each card wins one copy of
+// each of the next 'matches' cards, and every copy of a card wins in the same
+// way as the original. Copies are never won past the end of the table.
+func processCards(cards []*scratchcard) []cardResult {
+	results := make([]cardResult, len(cards))
+	for i, card := range cards {
+		results[i].id = card.id
+		results[i].matches = card.matchCount()
+		results[i].points = calculateScore(results[i].matches)
+		results[i].totalCopies = 1
+	}
+
+	for i := range results {
+		for j := i + 1; j <= i+results[i].matches && j < len(results); j++ {
+			// Increment by the number of copies that we currently have. For
+			// example, if we have three scratchcards for round 5 and have two
+			// winning numbers, then we will get three extra scratchcards for
+			// round 6 and round 7.
+			results[j].totalCopies += results[i].totalCopies
+			results[i].copiesWon++
 		}
+	}
+
+	return results
+}
 
-		// Increment by the number of copies that we currently have. For
-		// example, if we have three scratchcards for round 5 and have two
-		// winning numbers, then we will get three extra scratchcards for round
-		// 6 and round 7.
-		scratchcards[i] += scratchcards[roundNumber]
+// printReport prints a table containing the outcome of each card.
+func printReport(results []cardResult) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "Card\tMatches\tPoints\tCopies won\tTotal copies")
+	for _, result := range results {
+		fmt.Fprintf(writer, "%d\t%d\t%d\t%d\t%d\n",
+			result.id,
+			result.matches,
+			result.points,
+			result.copiesWon,
+			result.totalCopies,
+		)
 	}
+	writer.Flush()
 }
 
 func main() {
-	fileLines := fileutils.LoadFile("input.txt")
+	score := flag.String("score", "copies", "The total to report, either 'points' (part 1) or 'copies' (part 2).")
+	showReport := flag.Bool("report", false, "Print a table containing the outcome of each card.")
+	flag.Parse()
 
-	// Assemble the initial scratchcards array. We begin with 1 copy of every
-	// scratchcard/game round (except "game 0"), so initialize all real values
-	// to 1.
-	scratchcards = make([]int, len(fileLines)+1)
-	for i := range scratchcards {
-		scratchcards[i] = 1
+	if *score != "points" && *score != "copies" {
+		log.Fatalf("Unsupported score '%s', use 'points' or 'copies'.", *score)
 	}
-	scratchcards[0] = 0
 
-	for i, line := range fileLines {
-		processRound(line, i+1)
+	fileLines := fileutils.LoadFile("input.txt")
+	cards := make([]*scratchcard, 0, len(fileLines))
+	for _, line := range fileLines {
+		card, err := parseCard(line)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cards = append(cards, card)
+	}
+
+	results := processCards(cards)
+	if *showReport {
+		printReport(results)
+		fmt.Println()
 	}
 
-	sum := 0
-	for _, val := range scratchcards {
-		sum += val
+	points := 0
+	copies := 0
+	for _, result := range results {
+		points += result.points
+		copies += result.totalCopies
 	}
 
-	fmt.Printf("The total number of scratchcards collected is %d.\n", sum)
+	if *score == "points" {
+		fmt.Printf("The total number of points on the scratchcards is %d.\n", points)
+	} else {
+		fmt.Printf("The total number of scratchcards collected is %d.\n", copies)
+	}
 }
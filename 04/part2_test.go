@@ -0,0 +1,117 @@
+package main
+
+import (
+	utils "kqarryzada/advent-of-code-2023/utils"
+	"testing"
+)
+
+// loadCards parses every card of an input file.
+func loadCards(t *testing.T, filename string) []*scratchcard {
+	cards := make([]*scratchcard, 0)
+	for _, line := range utils.LoadFile(filename) {
+		card, err := parseCard(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cards = append(cards, card)
+	}
+
+	return cards
+}
+
+func Test_processCards(t *testing.T) {
+	results := processCards(loadCards(t, "example.txt"))
+
+	want := []cardResult{
+		{id: 1, matches: 4, points: 8, copiesWon: 4, totalCopies: 1},
+		{id: 2, matches: 2, points: 2, copiesWon: 2, totalCopies: 2},
+		{id: 3, matches: 2, points: 2, copiesWon: 2, totalCopies: 4},
+		{id: 4, matches: 1, points: 1, copiesWon: 1, totalCopies: 8},
+		{id: 5, matches: 0, points: 0, copiesWon: 0, totalCopies: 14},
+		{id: 6, matches: 0, points: 0, copiesWon: 0, totalCopies: 1},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("processCards() returned %d results, want %d", len(results), len(want))
+	}
+
+	points := 0
+	copies := 0
+	for i, result := range results {
+		if result != want[i] {
+			t.Errorf("card %d = %+v, want %+v", i+1, result, want[i])
+		}
+		points += result.points
+		copies += result.totalCopies
+	}
+
+	if points != 13 {
+		t.Errorf("total points = %v, want 13", points)
+	}
+	if copies != 30 {
+		t.Errorf("total copies = %v, want 30", copies)
+	}
+}
+
+// Test_processCards_endOfTable checks that copies are never won past the last
+// card, even when a card has more matches than there are cards after it.
+func Test_processCards_endOfTable(t *testing.T) {
+	cards := []*scratchcard{}
+	for _, line := range []string{
+		"Card 1: 1 2 3 | 1 2 3",
+		"Card 2: 4 5 | 4 5",
+	} {
+		card, err := parseCard(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cards = append(cards, card)
+	}
+
+	results := processCards(cards)
+	want := []cardResult{
+		{id: 1, matches: 3, points: 4, copiesWon: 1, totalCopies: 1},
+		{id: 2, matches: 2, points: 2, copiesWon: 0, totalCopies: 2},
+	}
+	for i, result := range results {
+		if result != want[i] {
+			t.Errorf("card %d = %+v, want %+v", i+1, result, want[i])
+		}
+	}
+}
+
+func Test_parseCard(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		wantID      int
+		wantMatches int
+		wantErr     bool
+	}{
+		{"Example card", "Card 1: 41 48 83 86 17 | 83 86  6 31 17  9 48 53", 1, 4, false},
+		{"Padded card number", "Card   12:  1  2 |  2  3", 12, 1, false},
+		{"Missing colon", "Card 1 41 48 | 41", 0, 0, true},
+		{"Missing separator", "Card 1: 41 48 41", 0, 0, true},
+		{"Non-numeric field", "Card 1: 41 4x | 41", 0, 0, true},
+		{"Non-numeric winning number", "Card 1: 41 | 41 y", 0, 0, true},
+		{"Missing card keyword", "Game 1: 41 | 41", 0, 0, true},
+		{"Invalid card number", "Card A: 41 | 41", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card, err := parseCard(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCard() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if card.id != tt.wantID {
+				t.Errorf("parseCard() id = %v, want %v", card.id, tt.wantID)
+			}
+			if got := card.matchCount(); got != tt.wantMatches {
+				t.Errorf("matchCount() = %v, want %v", got, tt.wantMatches)
+			}
+		})
+	}
+}
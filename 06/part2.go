@@ -1,12 +1,20 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	fileutils "kqarryzada/advent-of-code-2023/utils"
+	"log"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
 )
 
+// The largest race time for which T^2 can be computed without overflowing an
+// int64, which is floor(sqrt(2^63 - 1)).
+const MAX_EXACT_TIME = 3_037_000_499
+
 // calculateDistance computes the distance that a boat will travel given the
 // amount of time it is charged.
 func calculateDistance(chargeTime int, totalTime int) int {
@@ -14,20 +22,167 @@ func calculateDistance(chargeTime int, totalTime int) int {
 	return chargeTime * timeToMove
 }
 
+// A winningRange is the inclusive range of charge times that beat the record
+// of a race. The range is empty when 'first' is greater than 'last'.
+type winningRange struct {
+	first int
+	last  int
+}
+
+// count returns the number of charge times within the range.
+func (r winningRange) count() int {
+	return max(0, r.last-r.first+1)
+}
+
+func (r winningRange) String() string {
+	if r.count() == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%d-%d", r.first, r.last)
+}
+
+// isqrt returns floor(sqrt(n)) for a non-negative integer. The floating-point
+// estimate is corrected so that the result is exact for every int64.
+func isqrt(n int) int {
+	root := int(math.Sqrt(float64(n)))
+	for root*root > n {
+		root--
+	}
+	for root+1 <= n/(root+1) {
+		root++
+	}
+
+	return root
+}
+
+// findWinningRange returns the charge times 't' that beat the record distance
+// 'D' for a race of length 'T'. A charge time wins when t * (T - t) > D, which
+// holds strictly between the roots of t^2 - T*t + D = 0:
+//
+//	(T - sqrt(T^2 - 4D)) / 2 < t < (T + sqrt(T^2 - 4D)) / 2
+//
+// The roots are estimated with an integer square root, and then corrected by
+// checking the neighboring charge times. Since the distances are symmetric
+// around T/2, the last winning time is T minus the first. If T^2 or 4D cannot
+// be represented as an int64, the calculation is performed with math/big.
+func findWinningRange(totalTime int, recordDistance int) winningRange {
+	if totalTime < 2 {
+		// There are no charge times that allow the boat to move.
+		return winningRange{1, 0}
+	}
+	if recordDistance < 0 {
+		// Every charge time that moves the boat is a win.
+		return winningRange{1, totalTime - 1}
+	}
+	if totalTime > MAX_EXACT_TIME || recordDistance > math.MaxInt64/4 {
+		return findWinningRangeBig(totalTime, recordDistance)
+	}
+
+	discriminant := totalTime*totalTime - 4*recordDistance
+	if discriminant < 0 {
+		return winningRange{1, 0}
+	}
+
+	first := (totalTime - isqrt(discriminant)) / 2
+	for first > 1 && calculateDistance(first-1, totalTime) > recordDistance {
+		first--
+	}
+	for first <= totalTime/2 && calculateDistance(first, totalTime) <= recordDistance {
+		first++
+	}
+	first = max(first, 1)
+
+	return winningRange{first, totalTime - first}
+}
+
+// findWinningRangeBig is equivalent to findWinningRange, but performs the
+// calculation with arbitrary-precision integers so that large races do not
+// overflow.
+func findWinningRangeBig(totalTime int, recordDistance int) winningRange {
+	totalBig := big.NewInt(int64(totalTime))
+	recordBig := big.NewInt(int64(recordDistance))
+
+	discriminant := new(big.Int).Mul(totalBig, totalBig)
+	discriminant.Sub(discriminant, new(big.Int).Mul(big.NewInt(4), recordBig))
+	if discriminant.Sign() < 0 {
+		return winningRange{1, 0}
+	}
+
+	// wins reports whether the charge time 't' beats the record distance.
+	wins := func(t int) bool {
+		distance := new(big.Int).Mul(big.NewInt(int64(t)), big.NewInt(int64(totalTime-t)))
+		return distance.Cmp(recordBig) > 0
+	}
+
+	root := new(big.Int).Sqrt(discriminant)
+	first := int(new(big.Int).Sub(totalBig, root).Int64() / 2)
+	for first > 1 && wins(first-1) {
+		first--
+	}
+	for first <= totalTime/2 && !wins(first) {
+		first++
+	}
+	first = max(first, 1)
+
+	return winningRange{first, totalTime - first}
+}
+
+// parseValues reads the numbers that follow the label of a line, such as
+// "Time:  7  15  30". If 'join' is true, the numbers are treated as the digits
+// of a single number.
+func parseValues(line string, join bool) ([]int, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("expected a label followed by numbers: %s", line)
+	}
+	fields = fields[1:]
+	if join {
+		fields = []string{strings.Join(fields, "")}
+	}
+
+	values := make([]int, len(fields))
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s' in: %s", field, line)
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
 func main() {
+	separate := flag.Bool("separate", false, "Treat each column as a separate race and multiply the results (part 1).")
+	showRanges := flag.Bool("ranges", false, "Print the range of winning charge times for each race.")
+	flag.Parse()
+
 	fileLines := fileutils.LoadFile("input.txt")
-	timeString := strings.Join(strings.Fields(fileLines[0])[1:], "")
-	distanceString := strings.Join(strings.Fields(fileLines[1])[1:], "")
-	totalTime, _ := strconv.Atoi(timeString)
-	distanceToBeat, _ := strconv.Atoi(distanceString)
-
-	result := 0
-	for i := 1; i < totalTime; i++ {
-		score := calculateDistance(i, totalTime)
-		if score > distanceToBeat {
-			result++
+	times, err := parseValues(fileLines[0], !*separate)
+	if err != nil {
+		log.Fatal(err)
+	}
+	distances, err := parseValues(fileLines[1], !*separate)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(times) != len(distances) {
+		log.Fatalf("Found %d race times but %d record distances.", len(times), len(distances))
+	}
+
+	result := 1
+	for race := range times {
+		winning := findWinningRange(times[race], distances[race])
+		if *showRanges {
+			fmt.Printf("Race %d (time %d, record %d): %d winning charge times (%s)\n",
+				race+1, times[race], distances[race], winning.count(), winning)
 		}
+		result *= winning.count()
 	}
 
-	fmt.Printf("The number of winning combinations is %d.\n", result)
+	if *separate {
+		fmt.Printf("The product of the winning combinations is %d.\n", result)
+	} else {
+		fmt.Printf("The number of winning combinations is %d.\n", result)
+	}
 }
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+// bruteForce counts the winning charge times by checking each one, which
+// serves as a reference for findWinningRange.
+func bruteForce(totalTime int, recordDistance int) winningRange {
+	result := winningRange{1, 0}
+	for i := 1; i < totalTime; i++ {
+		if calculateDistance(i, totalTime) > recordDistance {
+			if result.count() == 0 {
+				result.first = i
+			}
+			result.last = i
+		}
+	}
+
+	return result
+}
+
+func Test_findWinningRange(t *testing.T) {
+	tests := []struct {
+		name           string
+		totalTime      int
+		recordDistance int
+		want           winningRange
+	}{
+		{"Example 1", 7, 9, winningRange{2, 5}},
+		{"Example 2", 15, 40, winningRange{4, 11}},
+		{"Example 3", 30, 200, winningRange{11, 19}},
+		{"Example combined", 71530, 940200, winningRange{14, 71516}},
+		{"Exact square root", 10, 24, winningRange{5, 5}},
+		{"Unbeatable record", 10, 25, winningRange{1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findWinningRange(tt.totalTime, tt.recordDistance)
+			if got.count() != tt.want.count() || (got.count() != 0 && got != tt.want) {
+				t.Errorf("findWinningRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_findWinningRange_bruteForce compares both solvers against the brute
+// force approach for every small race.
+func Test_findWinningRange_bruteForce(t *testing.T) {
+	for totalTime := 0; totalTime <= 60; totalTime++ {
+		for recordDistance := -2; recordDistance <= totalTime*totalTime/4+2; recordDistance++ {
+			want := bruteForce(totalTime, recordDistance)
+			got := findWinningRange(totalTime, recordDistance)
+			if got.count() != want.count() || (want.count() != 0 && got != want) {
+				t.Fatalf("findWinningRange(%d, %d) = %v, want %v", totalTime, recordDistance, got, want)
+			}
+
+			if totalTime < 2 || recordDistance < 0 {
+				continue
+			}
+			gotBig := findWinningRangeBig(totalTime, recordDistance)
+			if gotBig.count() != want.count() || (want.count() != 0 && gotBig != want) {
+				t.Fatalf("findWinningRangeBig(%d, %d) = %v, want %v", totalTime, recordDistance, gotBig, want)
+			}
+		}
+	}
+}
+
+// Test_findWinningRange_large checks the boundaries of races that are too long
+// for brute force, including those where T^2 overflows an int64.
+func Test_findWinningRange_large(t *testing.T) {
+	tests := []struct {
+		totalTime      int
+		recordDistance int
+	}{
+		{MAX_EXACT_TIME, 1_000_000_000_000_000},
+		{MAX_EXACT_TIME + 1, 1_000_000_000_000_000},
+		{5_000_000_000, 6_000_000_000_000_000_000},
+		{9_000_000_000_000_000_000, 123_456_789},
+	}
+	for _, tt := range tests {
+		got := findWinningRange(tt.totalTime, tt.recordDistance)
+		if got.count() == 0 {
+			t.Fatalf("findWinningRange(%d, %d) found no winning times", tt.totalTime, tt.recordDistance)
+		}
+
+		// The distances are computed with math/big, since they may overflow.
+		wins := func(chargeTime int) bool {
+			distance := new(big.Int).Mul(big.NewInt(int64(chargeTime)), big.NewInt(int64(tt.totalTime-chargeTime)))
+			return distance.Cmp(big.NewInt(int64(tt.recordDistance))) > 0
+		}
+		if !wins(got.first) || !wins(got.last) || wins(got.first-1) || wins(got.last+1) {
+			t.Errorf("findWinningRange(%d, %d) = %v has incorrect boundaries", tt.totalTime, tt.recordDistance, got)
+		}
+	}
+}
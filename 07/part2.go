@@ -1,239 +1,379 @@
 package main
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	fileutils "kqarryzada/advent-of-code-2023/utils"
+	"log"
+	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 )
 
+// The default deck definition. Part 1 of the puzzle has no wildcards, while
+// part 2 treats 'J' as a wildcard Joker.
+var DEFAULT_CARD_ORDER = "AKQJT98765432"
+var DEFAULT_HAND_SIZE = 5
+var DEFAULT_JOKER = "J"
+
 type pokerHand struct {
 	// The raw string representing the poker hand.
 	raw string
 
 	// The value of the poker hand (e.g., a Full House)
-	value pokerHandValue
+	value handSignature
+
+	// The value of the poker hand if wildcards were treated as regular cards.
+	baseValue handSignature
 
 	// The monetary bid associated with the poker hand.
 	bid int
 }
 
-type pokerHandValue int
+// A handSignature is the number of copies of each distinct card in a hand,
+// sorted from largest to smallest. For example, "KTJJT" has a signature of
+// [2, 2, 1]. Comparing signatures element by element ranks the hands in the
+// same order as the puzzle's hand types, regardless of the hand size.
+type handSignature []int
+
+// The names of the hand types for the standard hand size of 5 cards.
+var handValueNames = map[string]string{
+	"1+1+1+1+1": "High card",
+	"2+1+1+1":   "One pair",
+	"2+2+1":     "Two pair",
+	"3+1+1":     "Three of a kind",
+	"3+2":       "Full house",
+	"4+1":       "Four of a kind",
+	"5":         "Five of a kind",
+}
 
-const (
-	highCard pokerHandValue = iota
-	onePair
-	twoPair
-	threeOfAKind
-	fullHouse
-	fourOfAKind
-	fiveOfAKind
-)
+// String names the hand type. Hand types of non-standard sizes are described by
+// their signature, such as "3+3" for a hand of six cards.
+func (signature handSignature) String() string {
+	groups := make([]string, len(signature))
+	for i, count := range signature {
+		groups[i] = strconv.Itoa(count)
+	}
+	key := strings.Join(groups, "+")
 
-// containsValue returns true if the input map has the specified value stored in
-// the map, regardless of the key it is associated with.
-func containsValue(m map[byte]int, v int) bool {
-	for _, x := range m {
-		if x == v {
-			return true
-		}
+	if name, ok := handValueNames[key]; ok {
+		return name
 	}
+	return key
+}
 
-	return false
+// compare returns a negative number if the signature is weaker than the other
+// signature, a positive number if it is stronger, and zero if they are equal.
+func (signature handSignature) compare(other handSignature) int {
+	return slices.Compare(signature, other)
 }
 
-// computeWildcard takes an existing poker hand and returns the value of that
-// hand when wildcards are considered. For example, for a hand of four Aces and
-// a wildcard Joker, the value becomes a five-of-a-kind.
-func computeWildcard(currentHand pokerHandValue, numberWildcards int) pokerHandValue {
-	var returnHand pokerHandValue
-	if numberWildcards == 0 {
-		return currentHand
-	}
-	if numberWildcards == 5 {
-		return fiveOfAKind
-	}
-
-	if numberWildcards == 1 {
-		switch currentHand {
-		case highCard:
-			returnHand = onePair
-		case onePair:
-			returnHand = threeOfAKind
-		case twoPair:
-			returnHand = fullHouse
-		case threeOfAKind:
-			returnHand = fourOfAKind
-		case fourOfAKind:
-			returnHand = fiveOfAKind
-		default:
-			panic(fmt.Sprintf("Unexpected hand type with %d wildcard: %d", numberWildcards, currentHand))
-		}
+// deckConfig is the definition of a deck and its rules, which can be loaded
+// from a JSON file. For example:
+//
+//	{"order": "AKQT98765432J", "handSize": 5, "wildcards": "J"}
+type deckConfig struct {
+	// The cards of the deck, listed from the strongest to the weakest. Each
+	// card is a single character.
+	Order string `json:"order"`
 
-		return returnHand
-	}
+	// The number of cards in each hand.
+	HandSize int `json:"handSize"`
 
-	if numberWildcards == 2 {
-		switch currentHand {
-		case onePair:
-			returnHand = threeOfAKind
-		case twoPair:
-			returnHand = fourOfAKind
-		case fullHouse:
-			returnHand = fiveOfAKind
-		default:
-			panic(fmt.Sprintf("Unexpected hand type with %d wildcards: %d", numberWildcards, currentHand))
-		}
+	// The cards that act as wildcards, if any.
+	Wildcards string `json:"wildcards"`
+}
 
-		return returnHand
+// loadDeckConfig reads a deck definition from a JSON file.
+func loadDeckConfig(filename string) (deckConfig, error) {
+	config := deckConfig{}
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return config, err
 	}
 
-	if numberWildcards == 3 {
-		switch currentHand {
-		case threeOfAKind:
-			returnHand = fourOfAKind
-		case fullHouse:
-			returnHand = fiveOfAKind
-		default:
-			panic(fmt.Sprintf("Unexpected hand type with %d wildcards: %d", numberWildcards, currentHand))
-		}
-
-		return returnHand
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return config, fmt.Errorf("invalid deck definition in %s: %w", filename, err)
 	}
 
-	if numberWildcards == 4 {
-		switch currentHand {
-		case fourOfAKind:
-			returnHand = fiveOfAKind
-		default:
-			panic(fmt.Sprintf("Unexpected hand type with %d wildcards: %d", numberWildcards, currentHand))
-		}
+	return config, nil
+}
 
-		return returnHand
-	}
+// rules describes how the cards of a hand are ranked.
+type rules struct {
+	// The strength of each card, where a larger value is stronger.
+	strength map[byte]int
+
+	// The number of cards in each hand.
+	handSize int
 
-	panic(fmt.Sprintf("Unexpected number of wildcards: %d", numberWildcards))
+	// The wildcard cards. A wildcard acts as whichever card makes the hand
+	// strongest, but is weaker than every regular card when breaking ties.
+	wildcards map[byte]bool
 }
 
-// constructHand assembles a pokerHand based on an input string. For example,
-// "AAAQ4" would return a pokerHand object that identifies the string as a
-// three-of-a-kind.
-func constructHand(rawHand string) pokerHand {
-	if len(rawHand) != 5 {
-		panic("Improper input: " + rawHand)
+// newRules creates a set of rules from a deck definition.
+func newRules(config deckConfig) (*rules, error) {
+	r := &rules{
+		strength:  make(map[byte]int),
+		handSize:  config.HandSize,
+		wildcards: make(map[byte]bool),
 	}
 
-	retval := new(pokerHand)
-	retval.raw = rawHand
+	if len(config.Order) == 0 {
+		return nil, fmt.Errorf("the card order cannot be empty")
+	}
+	if config.HandSize < 1 {
+		return nil, fmt.Errorf("the hand size must be at least 1, but %d was provided", config.HandSize)
+	}
 
-	mapVals := make(map[byte]int)
-	mapVals[rawHand[0]] = 1
-	for i := 1; i < len(rawHand); i++ {
-		char := rawHand[i]
-		mapVals[char]++
+	for i := 0; i < len(config.Wildcards); i++ {
+		r.wildcards[config.Wildcards[i]] = true
 	}
 
-	// A map of length 1 indicates that all values are identical.
-	if len(mapVals) == 1 {
-		retval.value = fiveOfAKind
-		return *retval
+	// Regular cards are ranked above every wildcard, while each group keeps
+	// the relative order of the card order.
+	regularCount := 0
+	for i := 0; i < len(config.Order); i++ {
+		if !r.wildcards[config.Order[i]] {
+			regularCount++
+		}
 	}
 
-	incrementValue := 0
-	incrementValue = mapVals['J']
+	nextRegular := len(config.Order)
+	nextWildcard := len(config.Order) - regularCount
+	for i := 0; i < len(config.Order); i++ {
+		card := config.Order[i]
+		if _, ok := r.strength[card]; ok {
+			return nil, fmt.Errorf("the card '%c' appears more than once in the card order", card)
+		}
 
-	// Compute each value as if J corresponds to a Jack instead of a wildcard
-	// Joker. Then, pass the expected value into the computeWildcard method to
-	// obtain the true value.
-	if len(mapVals) == 5 {
-		retval.value = computeWildcard(highCard, incrementValue)
-		return *retval
+		if r.wildcards[card] {
+			r.strength[card] = nextWildcard
+			nextWildcard--
+		} else {
+			r.strength[card] = nextRegular
+			nextRegular--
+		}
 	}
-	if containsValue(mapVals, 4) {
-		retval.value = computeWildcard(fourOfAKind, incrementValue)
-		return *retval
+
+	for card := range r.wildcards {
+		if _, ok := r.strength[card]; !ok {
+			return nil, fmt.Errorf("the wildcard '%c' is not in the card order", card)
+		}
 	}
-	if containsValue(mapVals, 3) {
-		baseHandType := threeOfAKind
-		if len(mapVals) == 2 {
-			baseHandType = fullHouse
+
+	return r, nil
+}
+
+// classify determines the signature of a hand. Any wildcards are added to the
+// largest group, since that always forms the best hand. When 'useWildcards' is
+// false, wildcards are treated as regular cards.
+func (r *rules) classify(rawHand string, useWildcards bool) handSignature {
+	counts := make(map[byte]int)
+	wildcards := 0
+	for i := 0; i < len(rawHand); i++ {
+		card := rawHand[i]
+		if useWildcards && r.wildcards[card] {
+			wildcards++
+		} else {
+			counts[card]++
 		}
+	}
 
-		retval.value = computeWildcard(baseHandType, incrementValue)
-		return *retval
+	signature := make(handSignature, 0, len(counts))
+	for _, count := range counts {
+		signature = append(signature, count)
 	}
-	if len(mapVals) == 3 {
-		retval.value = computeWildcard(twoPair, incrementValue)
-		return *retval
+	slices.Sort(signature)
+	slices.Reverse(signature)
+
+	if len(signature) == 0 {
+		// Every card in the hand is a wildcard.
+		signature = append(signature, 0)
 	}
+	signature[0] += wildcards
 
-	retval.value = computeWildcard(onePair, incrementValue)
-	return *retval
+	return signature
 }
 
-func convertCardToInt(char byte) int {
-	var num int
-	if char >= '2' && char <= '9' {
-		num = int(char - '0')
-	} else if char == 'T' {
-		num = 10
-	} else if char == 'J' {
-		num = 1
-	} else if char == 'Q' {
-		num = 12
-	} else if char == 'K' {
-		num = 13
-	} else if char == 'A' {
-		num = 14
-	} else {
-		panic("Invalid character found: " + string(char))
-	}
-
-	return num
+// constructHand assembles a pokerHand based on an input string. For example,
+// "AAAQ4" would return a pokerHand object that identifies the string as a
+// three-of-a-kind.
+func (r *rules) constructHand(rawHand string) pokerHand {
+	if len(rawHand) != r.handSize {
+		panic(fmt.Sprintf("Improper input, expected %d cards: %s", r.handSize, rawHand))
+	}
+
+	for i := 0; i < len(rawHand); i++ {
+		if _, ok := r.strength[rawHand[i]]; !ok {
+			panic("Invalid character found: " + string(rawHand[i]))
+		}
+	}
+
+	return pokerHand{
+		raw:       rawHand,
+		value:     r.classify(rawHand, true),
+		baseValue: r.classify(rawHand, false),
+	}
 }
 
 // compareHands evaluates two poker hands and returns true if the first poker
-// hand is losing against the second poker hand.
-func compareHands(firstHand pokerHand, secondHand pokerHand) bool {
-	if firstHand.value != secondHand.value {
-		return firstHand.value < secondHand.value
+// hand is losing against the second poker hand. Identical hands are not losing
+// against each other, so a stable sort keeps them in their input order.
+func (r *rules) compareHands(firstHand pokerHand, secondHand pokerHand) bool {
+	if comparison := firstHand.value.compare(secondHand.value); comparison != 0 {
+		return comparison < 0
 	}
 
 	for i := range firstHand.raw {
-		val1 := convertCardToInt(firstHand.raw[i])
-		val2 := convertCardToInt(secondHand.raw[i])
+		val1 := r.strength[firstHand.raw[i]]
+		val2 := r.strength[secondHand.raw[i]]
 
 		if val1 != val2 {
 			return val1 < val2
 		}
 	}
 
-	panic("Two identical hands were found.")
+	return false
+}
+
+// standing is a single entry of the ranked standings report.
+type standing struct {
+	Rank     int    `json:"rank"`
+	Hand     string `json:"hand"`
+	Type     string `json:"type"`
+	BaseType string `json:"typeWithoutJokers"`
+	Bid      int    `json:"bid"`
+	Winnings int    `json:"winnings"`
+}
+
+// rankHands sorts the hands from the weakest to the strongest and returns the
+// resulting standings. Identical hands are ranked in the order in which they
+// appear in the input file, with the earlier hand receiving the lower rank.
+func (r *rules) rankHands(handList []pokerHand) []standing {
+	sort.SliceStable(handList, func(i, j int) bool {
+		return r.compareHands(handList[i], handList[j])
+	})
+
+	standings := make([]standing, len(handList))
+	for i, handEntry := range handList {
+		rank := i + 1
+		standings[i] = standing{
+			Rank:     rank,
+			Hand:     handEntry.raw,
+			Type:     handEntry.value.String(),
+			BaseType: handEntry.baseValue.String(),
+			Bid:      handEntry.bid,
+			Winnings: rank * handEntry.bid,
+		}
+	}
+
+	return standings
+}
+
+// writeReport prints the standings in the requested format, which must be one
+// of 'table', 'csv', or 'json'.
+func writeReport(writer io.Writer, standings []standing, format string) error {
+	switch format {
+	case "table":
+		tableWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tableWriter, "Rank\tHand\tType\tType without jokers\tBid\tWinnings")
+		for _, s := range standings {
+			fmt.Fprintf(tableWriter, "%d\t%s\t%s\t%s\t%d\t%d\n", s.Rank, s.Hand, s.Type, s.BaseType, s.Bid, s.Winnings)
+		}
+		return tableWriter.Flush()
+
+	case "csv":
+		csvWriter := csv.NewWriter(writer)
+		csvWriter.Write([]string{"rank", "hand", "type", "type_without_jokers", "bid", "winnings"})
+		for _, s := range standings {
+			csvWriter.Write([]string{
+				strconv.Itoa(s.Rank),
+				s.Hand,
+				s.Type,
+				s.BaseType,
+				strconv.Itoa(s.Bid),
+				strconv.Itoa(s.Winnings),
+			})
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+
+	case "json":
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(standings)
+
+	default:
+		return fmt.Errorf("unsupported report format '%s', use 'table', 'csv', or 'json'", format)
+	}
 }
 
 func main() {
+	cardOrder := flag.String("order", DEFAULT_CARD_ORDER, "The cards of the deck, listed from the strongest to the weakest.")
+	handSize := flag.Int("size", DEFAULT_HAND_SIZE, "The number of cards in each hand.")
+	joker := flag.String("joker", DEFAULT_JOKER, "The wildcard cards. Use an empty value to play without wildcards.")
+	rulesFile := flag.String("rules", "", "Load the deck definition from a JSON file instead of the -order, -size, and -joker flags.")
+	report := flag.String("report", "", "Print the ranked standings of every hand as a 'table', 'csv', or 'json'.")
+	flag.Parse()
+
+	config := deckConfig{Order: *cardOrder, HandSize: *handSize, Wildcards: *joker}
+	if *rulesFile != "" {
+		var err error
+		config, err = loadDeckConfig(*rulesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	gameRules, err := newRules(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	fileLines := fileutils.LoadFile("input.txt")
 
 	// Assemble the poker hands as a slice of pokerHand objects.
 	handList := make([]pokerHand, 0)
 	for _, line := range fileLines {
 		values := strings.Fields(line)
-		parsedHand := constructHand(values[0])
-		parsedHand.bid, _ = strconv.Atoi(values[1])
+		parsedHand := gameRules.constructHand(values[0])
+		parsedHand.bid, err = strconv.Atoi(values[1])
+		if err != nil {
+			panic("Invalid bid found: " + values[1])
+		}
 
 		handList = append(handList, parsedHand)
 	}
 
-	// Sort the slice with the worst poker hand listed first.
-	sort.Slice(handList, func(i, j int) bool {
-		return compareHands(handList[i], handList[j])
-	})
+	// Rank the hands with the worst poker hand listed first.
+	standings := gameRules.rankHands(handList)
+	if *report != "" {
+		if err := writeReport(os.Stdout, standings, *report); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	totalWinnings := 0
-	for i, handEntry := range handList {
-		totalWinnings += (i + 1) * handEntry.bid
+	for _, s := range standings {
+		totalWinnings += s.Winnings
 	}
 
-	fmt.Printf("The total winnings across all the poker hands are %d.\n", totalWinnings)
+	// The summary is kept out of machine-readable reports, so that the output
+	// can be passed directly to other tools.
+	summaryWriter := os.Stdout
+	if *report == "csv" || *report == "json" {
+		summaryWriter = os.Stderr
+	}
+	fmt.Fprintf(summaryWriter, "The total winnings across all the poker hands are %d.\n", totalWinnings)
 }
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// JOKER_RULES are the rules of part 2 of the puzzle.
+var JOKER_RULES = deckConfig{Order: DEFAULT_CARD_ORDER, HandSize: DEFAULT_HAND_SIZE, Wildcards: DEFAULT_JOKER}
+
+// rankExample ranks the hands of the puzzle's example with the provided rules.
+func rankExample(t *testing.T, config deckConfig) []standing {
+	gameRules, err := newRules(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := []struct {
+		raw string
+		bid int
+	}{{"32T3K", 765}, {"T55J5", 684}, {"KK677", 28}, {"KTJJT", 220}, {"QQQJA", 483}}
+
+	handList := make([]pokerHand, 0)
+	for _, line := range lines {
+		hand := gameRules.constructHand(line.raw)
+		hand.bid = line.bid
+		handList = append(handList, hand)
+	}
+
+	return gameRules.rankHands(handList)
+}
+
+// exampleStandings ranks the hands of the puzzle's example with the Joker
+// rules.
+func exampleStandings(t *testing.T) []standing {
+	return rankExample(t, JOKER_RULES)
+}
+
+// totalWinnings sums the winnings of the standings.
+func totalWinnings(standings []standing) int {
+	total := 0
+	for _, s := range standings {
+		total += s.Winnings
+	}
+
+	return total
+}
+
+func Test_classify(t *testing.T) {
+	tests := []struct {
+		name         string
+		hand         string
+		useWildcards bool
+		want         handSignature
+		wantName     string
+	}{
+		{"All jokers", "JJJJJ", true, handSignature{5}, "Five of a kind"},
+		{"All jacks", "JJJJJ", false, handSignature{5}, "Five of a kind"},
+		{"Four jokers", "JJJJ2", true, handSignature{5}, "Five of a kind"},
+		{"Four jacks", "JJJJ2", false, handSignature{4, 1}, "Four of a kind"},
+		{"Two jokers", "KTJJT", true, handSignature{4, 1}, "Four of a kind"},
+		{"Two jacks", "KTJJT", false, handSignature{2, 2, 1}, "Two pair"},
+		{"One joker", "T55J5", true, handSignature{4, 1}, "Four of a kind"},
+		{"Full house", "33322", true, handSignature{3, 2}, "Full house"},
+		{"Joker with a pair", "32T3J", true, handSignature{3, 1, 1}, "Three of a kind"},
+		{"High card", "23456", true, handSignature{1, 1, 1, 1, 1}, "High card"},
+	}
+
+	gameRules, err := newRules(JOKER_RULES)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := gameRules.classify(tt.hand, tt.useWildcards)
+			if got.compare(tt.want) != 0 {
+				t.Errorf("classify() = %v, want %v", []int(got), []int(tt.want))
+			}
+			if got.String() != tt.wantName {
+				t.Errorf("classify().String() = %v, want %v", got.String(), tt.wantName)
+			}
+		})
+	}
+}
+
+func Test_rankHands_withoutJokers(t *testing.T) {
+	standings := rankExample(t, deckConfig{Order: DEFAULT_CARD_ORDER, HandSize: DEFAULT_HAND_SIZE})
+
+	wantOrder := []string{"32T3K", "KTJJT", "KK677", "T55J5", "QQQJA"}
+	for i, s := range standings {
+		if s.Hand != wantOrder[i] {
+			t.Errorf("standings[%d] = %s, want %s", i, s.Hand, wantOrder[i])
+		}
+	}
+
+	if got := totalWinnings(standings); got != 6440 {
+		t.Errorf("total winnings = %d, want 6440", got)
+	}
+}
+
+func Test_rankHands(t *testing.T) {
+	standings := exampleStandings(t)
+
+	wantOrder := []string{"32T3K", "KK677", "T55J5", "QQQJA", "KTJJT"}
+	for i, s := range standings {
+		if s.Hand != wantOrder[i] || s.Rank != i+1 {
+			t.Errorf("standings[%d] = %s with rank %d, want %s with rank %d", i, s.Hand, s.Rank, wantOrder[i], i+1)
+		}
+	}
+
+	if got := totalWinnings(standings); got != 5905 {
+		t.Errorf("total winnings = %d, want 5905", got)
+	}
+}
+
+// Test_rankHands_identicalHands checks that identical hands are ranked in the
+// order in which they appear in the input.
+func Test_rankHands_identicalHands(t *testing.T) {
+	gameRules, err := newRules(JOKER_RULES)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handList := make([]pokerHand, 0)
+	for i, raw := range []string{"KK677", "23456", "KK677", "KK677"} {
+		hand := gameRules.constructHand(raw)
+		hand.bid = i + 1
+		handList = append(handList, hand)
+	}
+
+	standings := gameRules.rankHands(handList)
+	gotBids := make([]int, len(standings))
+	for i, s := range standings {
+		gotBids[i] = s.Bid
+	}
+
+	if wantBids := []int{2, 1, 3, 4}; !slices.Equal(gotBids, wantBids) {
+		t.Errorf("bids in rank order = %v, want %v", gotBids, wantBids)
+	}
+}
+
+func Test_writeReport_json(t *testing.T) {
+	standings := exampleStandings(t)
+
+	var buffer bytes.Buffer
+	if err := writeReport(&buffer, standings, "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded []standing
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+		t.Fatalf("the JSON report is invalid: %v", err)
+	}
+	if !slices.Equal(decoded, standings) {
+		t.Errorf("decoded report = %v, want %v", decoded, standings)
+	}
+}
+
+func Test_writeReport_csv(t *testing.T) {
+	standings := exampleStandings(t)
+
+	var buffer bytes.Buffer
+	if err := writeReport(&buffer, standings, "csv"); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("the CSV report is invalid: %v", err)
+	}
+	if len(records) != len(standings)+1 {
+		t.Fatalf("found %d records, want a header and %d rows", len(records), len(standings))
+	}
+
+	for i, s := range standings {
+		want := []string{
+			strconv.Itoa(s.Rank),
+			s.Hand,
+			s.Type,
+			s.BaseType,
+			strconv.Itoa(s.Bid),
+			strconv.Itoa(s.Winnings),
+		}
+		if !slices.Equal(records[i+1], want) {
+			t.Errorf("row %d = %v, want %v", i+1, records[i+1], want)
+		}
+	}
+}
+
+func Test_writeReport_unsupported(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := writeReport(&buffer, exampleStandings(t), "xml"); err == nil {
+		t.Error("writeReport() did not return an error for an unsupported format")
+	}
+}
+
+func Test_newRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  deckConfig
+		wantErr string
+	}{
+		{"Standard deck", JOKER_RULES, ""},
+		{"Several wildcards", deckConfig{Order: "AKQJT98765432", HandSize: 5, Wildcards: "J2"}, ""},
+		{"Empty order", deckConfig{Order: "", HandSize: 5}, "cannot be empty"},
+		{"Hand size of zero", deckConfig{Order: "AKQ", HandSize: 0}, "at least 1"},
+		{"Negative hand size", deckConfig{Order: "AKQ", HandSize: -2}, "at least 1"},
+		{"Duplicate card", deckConfig{Order: "AKQK", HandSize: 5}, "'K' appears more than once"},
+		{"Wildcard missing from the order", deckConfig{Order: "AKQ", HandSize: 5, Wildcards: "J"}, "'J' is not in the card order"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newRules(tt.config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("newRules() error = %v, want none", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("newRules() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Test_newRules_strength checks that every wildcard is weaker than every
+// regular card, while each group keeps the relative order of the card order.
+func Test_newRules_strength(t *testing.T) {
+	gameRules, err := newRules(deckConfig{Order: "AKQJT", HandSize: 5, Wildcards: "QT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := []byte{'A', 'K', 'J', 'Q', 'T'}
+	for i := 1; i < len(order); i++ {
+		if gameRules.strength[order[i-1]] <= gameRules.strength[order[i]] {
+			t.Errorf("'%c' is not stronger than '%c'", order[i-1], order[i])
+		}
+	}
+}
+
+func Test_sixCardHands(t *testing.T) {
+	gameRules, err := newRules(deckConfig{Order: DEFAULT_CARD_ORDER, HandSize: 6, Wildcards: "J"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		hand     string
+		want     handSignature
+		wantName string
+	}{
+		{"AAAKKK", handSignature{3, 3}, "3+3"},
+		{"AAKKQQ", handSignature{2, 2, 2}, "2+2+2"},
+		{"AAKKQJ", handSignature{3, 2, 1}, "3+2+1"},
+		{"23456J", handSignature{2, 1, 1, 1, 1}, "2+1+1+1+1"},
+		{"JJJJJJ", handSignature{6}, "6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hand, func(t *testing.T) {
+			hand := gameRules.constructHand(tt.hand)
+			if hand.value.compare(tt.want) != 0 || hand.value.String() != tt.wantName {
+				t.Errorf("constructHand().value = %v (%s), want %v (%s)", []int(hand.value), hand.value, []int(tt.want), tt.wantName)
+			}
+		})
+	}
+
+	// A 3+3 hand beats a 3+2+1 hand, but loses to four of a kind.
+	threeThree := gameRules.constructHand("AAAKKK")
+	if !gameRules.compareHands(gameRules.constructHand("AAKKQJ"), threeThree) {
+		t.Error("AAKKQJ should lose against AAAKKK")
+	}
+	if !gameRules.compareHands(threeThree, gameRules.constructHand("2222KQ")) {
+		t.Error("AAAKKK should lose against 2222KQ")
+	}
+}
+
+func Test_loadDeckConfig(t *testing.T) {
+	config, err := loadDeckConfig("rules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config != JOKER_RULES {
+		t.Errorf("loadDeckConfig() = %+v, want %+v", config, JOKER_RULES)
+	}
+
+	if got := totalWinnings(rankExample(t, config)); got != 5905 {
+		t.Errorf("total winnings = %d, want 5905", got)
+	}
+}
+
+func Test_loadDeckConfig_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"Unknown field", `{"order": "AKQ", "handSize": 5, "jokers": "J"}`},
+		{"Wrong type", `{"order": "AKQ", "handSize": "five"}`},
+		{"Invalid JSON", `{"order": `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "rules.json")
+			if err := os.WriteFile(filename, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := loadDeckConfig(filename); err == nil {
+				t.Error("loadDeckConfig() did not return an error")
+			}
+		})
+	}
+
+	if _, err := loadDeckConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("loadDeckConfig() did not return an error for a missing file")
+	}
+}
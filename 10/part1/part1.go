@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	utils "kqarryzada/advent-of-code-2023/utils"
+	"log"
 	"slices"
 )
 
@@ -186,34 +187,111 @@ func appendToQueue(queue []*node, inputNode *node, distance int) []*node {
 	return append(queue, inputNode)
 }
 
-func computeGraph(inputLines *[]string) int {
+// loopInfo describes the closed loop that passes through the starting node.
+type loopInfo struct {
+	// The nodes of the loop in the order that they are visited when following
+	// the pipes. The starting node is the first element.
+	path []*node
+
+	// The largest distance from the starting node to any node in the loop, and
+	// the nodes found at that distance.
+	maxDistance int
+	farthest    []*node
+}
+
+// neighbors returns the nodes that are connected to the input node through its
+// pipe openings.
+func neighbors(inputNode *node) []*node {
+	connected := make([]*node, 0, 2)
+	for _, neighbor := range []*node{
+		fetchNorthNeighbor(inputNode),
+		fetchSouthNeighbor(inputNode),
+		fetchWestNeighbor(inputNode),
+		fetchEastNeighbor(inputNode),
+	} {
+		if neighbor != nil {
+			connected = append(connected, neighbor)
+		}
+	}
+
+	return connected
+}
+
+// walkLoop returns the nodes of the loop in the order that they are visited
+// when following the pipes from the starting node through 'first' and back to
+// the starting node through 'last'. An error is returned if the pipes do not
+// lead back to the starting node.
+func walkLoop(startingNode *node, first *node, last *node) ([]*node, error) {
+	path := []*node{startingNode}
+
+	previous := startingNode
+	current := first
+	for {
+		path = append(path, current)
+		if current == last {
+			return path, nil
+		}
+
+		// The fetch functions never return the starting node, so the only
+		// neighbor that can be followed is the one that was not just visited.
+		var next *node
+		for _, neighbor := range neighbors(current) {
+			if neighbor != previous {
+				next = neighbor
+				break
+			}
+		}
+
+		if next == nil {
+			return nil, fmt.Errorf("the loop is not closed: the pipe ends at (%d, %d)", current.row, current.col)
+		}
+		previous, current = current, next
+	}
+}
+
+// This function finds the loop contained within the graph. For each node in the
+// loop, this function computes its distance from the starting point with a
+// breadth-first search. An error is returned if the starting node does not
+// connect to exactly two pipes, or if those pipes do not form a closed loop.
+func computeGraph() (*loopInfo, error) {
 	lineNumber, charIndex := findStartingPoint()
 	startingNode := graph[lineNumber][charIndex]
 	startingNode.distance = 0
 
+	startNeighbors := neighbors(startingNode)
+	if len(startNeighbors) != 2 {
+		return nil, fmt.Errorf("the starting character at (%d, %d) connects to %d pipes, but exactly 2 are required", lineNumber, charIndex, len(startNeighbors))
+	}
+
+	path, err := walkLoop(startingNode, startNeighbors[0], startNeighbors[1])
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize the queue. Use the standard append() function since
 	// appendToQueue() avoids adding the starting node.
 	queue := make([]*node, 0)
 	queue = append(queue, startingNode)
 
-	maxDistance := 0
-	var neighbors [4](*node)
+	info := &loopInfo{path: path}
 	for len(queue) > 0 {
 		currentNode := queue[0]
 		queue = queue[1:]
-		distance := currentNode.distance + 1
-		maxDistance = max(maxDistance, distance)
-
-		neighbors[0] = fetchNorthNeighbor(currentNode)
-		neighbors[1] = fetchSouthNeighbor(currentNode)
-		neighbors[2] = fetchWestNeighbor(currentNode)
-		neighbors[3] = fetchEastNeighbor(currentNode)
-		for _, neighbor := range neighbors {
-			queue = appendToQueue(queue, neighbor, distance)
+
+		if currentNode.distance > info.maxDistance {
+			info.maxDistance = currentNode.distance
+			info.farthest = info.farthest[:0]
+		}
+		if currentNode.distance == info.maxDistance {
+			info.farthest = append(info.farthest, currentNode)
+		}
+
+		for _, neighbor := range neighbors(currentNode) {
+			queue = appendToQueue(queue, neighbor, currentNode.distance+1)
 		}
 	}
 
-	return maxDistance - 1
+	return info, nil
 }
 
 func findStartingPoint() (x int, y int) {
@@ -282,6 +360,14 @@ func initializeGraph(inputFile *[]string) {
 func main() {
 	fileLines := utils.LoadFile("input.txt")
 	initializeGraph(&fileLines)
-	maxDistance := computeGraph(&fileLines)
-	fmt.Printf("The largest distance found was %d.\n", maxDistance)
+	loop, err := computeGraph()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("The loop contains %d tiles.\n", len(loop.path))
+	for _, farthestNode := range loop.farthest {
+		fmt.Printf("The farthest tile from the start is at (%d, %d).\n", farthestNode.row, farthestNode.col)
+	}
+	fmt.Printf("The largest distance found was %d.\n", loop.maxDistance)
 }
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	utils "kqarryzada/advent-of-code-2023/utils"
+	"log"
 	"slices"
+	"strings"
 )
 
 type node struct {
 	nodeType metalPieceType
 	distance int
 
+	// Indicates whether the node is surrounded by the loop. This is only set
+	// once findEnclosedValueCount() has been called.
+	isEnclosed bool
+
 	// Coordinate in the grid.
 	row int
 	col int
@@ -198,39 +205,111 @@ func appendToQueueWithDistance(queue []*node, inputNode *node, distance int) []*
 	return append(queue, inputNode)
 }
 
+// loopInfo describes the closed loop that passes through the starting node.
+type loopInfo struct {
+	// The nodes of the loop in the order that they are visited when following
+	// the pipes. The starting node is the first element.
+	path []*node
+
+	// The largest distance from the starting node to any node in the loop, and
+	// the nodes found at that distance.
+	maxDistance int
+	farthest    []*node
+}
+
+// neighbors returns the nodes that are connected to the input node through its
+// pipe openings.
+func neighbors(inputNode *node) []*node {
+	connected := make([]*node, 0, 2)
+	for _, neighbor := range []*node{
+		fetchNorthNeighbor(inputNode),
+		fetchSouthNeighbor(inputNode),
+		fetchWestNeighbor(inputNode),
+		fetchEastNeighbor(inputNode),
+	} {
+		if neighbor != nil {
+			connected = append(connected, neighbor)
+		}
+	}
+
+	return connected
+}
+
+// walkLoop returns the nodes of the loop in the order that they are visited
+// when following the pipes from the starting node through 'first' and back to
+// the starting node through 'last'. An error is returned if the pipes do not
+// lead back to the starting node.
+func walkLoop(startingNode *node, first *node, last *node) ([]*node, error) {
+	path := []*node{startingNode}
+
+	previous := startingNode
+	current := first
+	for {
+		path = append(path, current)
+		if current == last {
+			return path, nil
+		}
+
+		// The fetch functions never return the starting node, so the only
+		// neighbor that can be followed is the one that was not just visited.
+		var next *node
+		for _, neighbor := range neighbors(current) {
+			if neighbor != previous {
+				next = neighbor
+				break
+			}
+		}
+
+		if next == nil {
+			return nil, fmt.Errorf("the loop is not closed: the pipe ends at (%d, %d)", current.row, current.col)
+		}
+		previous, current = current, next
+	}
+}
+
 // This function finds the loop contained within the graph. For each node in the
-// loop, this function computes its distance from the starting point. The
-// largest value seen during this calculation is returned.
-func computeGraph(inputLines *[]string) int {
+// loop, this function computes its distance from the starting point with a
+// breadth-first search. An error is returned if the starting node does not
+// connect to exactly two pipes, or if those pipes do not form a closed loop.
+func computeGraph() (*loopInfo, error) {
 	lineNumber, charIndex := findStartingPoint()
 	startingNode := graph[lineNumber][charIndex]
 	startingNode.distance = 0
 
+	startNeighbors := neighbors(startingNode)
+	if len(startNeighbors) != 2 {
+		return nil, fmt.Errorf("the starting character at (%d, %d) connects to %d pipes, but exactly 2 are required", lineNumber, charIndex, len(startNeighbors))
+	}
+
+	path, err := walkLoop(startingNode, startNeighbors[0], startNeighbors[1])
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize the queue. Use the standard append() function since
-	// appendToQueue() avoids adding the starting node.
+	// appendToQueueWithDistance() avoids adding the starting node.
 	queue := make([]*node, 0)
 	queue = append(queue, startingNode)
 
-	maxDistance := 0
-	var neighbors [4](*node)
+	info := &loopInfo{path: path}
 	for len(queue) > 0 {
 		currentNode := queue[0]
 		queue = queue[1:]
-		distance := currentNode.distance + 1
-		maxDistance = max(maxDistance, distance)
-
-		neighbors[0] = fetchNorthNeighbor(currentNode)
-		neighbors[1] = fetchSouthNeighbor(currentNode)
-		neighbors[2] = fetchWestNeighbor(currentNode)
-		neighbors[3] = fetchEastNeighbor(currentNode)
-		for _, neighbor := range neighbors {
-			queue = appendToQueueWithDistance(queue, neighbor, distance)
+
+		if currentNode.distance > info.maxDistance {
+			info.maxDistance = currentNode.distance
+			info.farthest = info.farthest[:0]
+		}
+		if currentNode.distance == info.maxDistance {
+			info.farthest = append(info.farthest, currentNode)
+		}
+
+		for _, neighbor := range neighbors(currentNode) {
+			queue = appendToQueueWithDistance(queue, neighbor, currentNode.distance+1)
 		}
 	}
 
-	// The final iteration in the loop will increment the maxDistance, but that
-	// node will have no unvisited neighbors.
-	return maxDistance - 1
+	return info, nil
 }
 
 func findStartingPoint() (x int, y int) {
@@ -352,6 +431,7 @@ func findEnclosedValueCount() int {
 			if !isLoopMember(currentNode) {
 				if enclosed {
 					enclosedCount++
+					currentNode.isEnclosed = true
 				}
 				continue
 			}
@@ -392,9 +472,138 @@ func findEnclosedValueCount() int {
 	return enclosedCount
 }
 
+// findEnclosedValueCountByArea computes the number of enclosed tiles without
+// scanning the rows of the graph. The loop is treated as a polygon whose
+// vertices are the centers of its tiles. The shoelace formula provides the
+// area of this polygon, and Pick's theorem relates that area to the number of
+// interior points:
+//
+//	area = interior + (boundary / 2) - 1
+//
+// Since every tile of the loop is a boundary point, the interior points are
+// exactly the enclosed tiles.
+func findEnclosedValueCountByArea(path []*node) int {
+	// Compute twice the signed area of the polygon to avoid fractions.
+	doubleArea := 0
+	for i, current := range path {
+		next := path[(i+1)%len(path)]
+		doubleArea += current.col*next.row - next.col*current.row
+	}
+	doubleArea = max(doubleArea, -doubleArea)
+
+	boundary := len(path)
+	return (doubleArea-boundary)/2 + 1
+}
+
+// The characters used to display each type of metal piece in the input file, and
+// when rendering the loop with box-drawing characters.
+var inputCharacters = map[metalPieceType]rune{
+	period:   '.',
+	pipe:     '|',
+	dash:     '-',
+	elbowL:   'L',
+	elbowJ:   'J',
+	elbowF:   'F',
+	elbow7:   '7',
+	starting: 'S',
+}
+var boxCharacters = map[metalPieceType]rune{
+	pipe:   '│',
+	dash:   '─',
+	elbowL: '└',
+	elbowJ: '┘',
+	elbowF: '┌',
+	elbow7: '┐',
+}
+
+// ANSI escape codes used to highlight the rendered maze.
+const (
+	COLOR_LOOP     = "\033[1;32m"
+	COLOR_START    = "\033[1;31m"
+	COLOR_ENCLOSED = "\033[1;33m"
+	COLOR_RESET    = "\033[0m"
+)
+
+// renderMaze draws the graph with box-drawing characters. Pieces in the loop
+// are drawn with their box-drawing character, the starting node is drawn with
+// its inferred shape, enclosed tiles are marked with 'I', and all other tiles
+// are marked with '.'. When 'useColor' is true, the loop, the starting node,
+// and the enclosed tiles are highlighted with ANSI colors.
+//
+// This function expects computeGraph() and findEnclosedValueCount() to have
+// been called beforehand.
+func renderMaze(useColor bool) string {
+	var builder strings.Builder
+	for i := range graph {
+		for j := range graph[i] {
+			currentNode := graph[i][j]
+
+			var char rune
+			var color string
+			switch {
+			case isLoopMember(currentNode):
+				char = boxCharacters[getNodeType(currentNode)]
+				color = COLOR_LOOP
+				if currentNode.nodeType == starting {
+					color = COLOR_START
+				}
+			case currentNode.isEnclosed:
+				char = 'I'
+				color = COLOR_ENCLOSED
+			default:
+				char = '.'
+			}
+
+			if useColor && color != "" {
+				builder.WriteString(color)
+				builder.WriteRune(char)
+				builder.WriteString(COLOR_RESET)
+			} else {
+				builder.WriteRune(char)
+			}
+		}
+		builder.WriteRune('\n')
+	}
+
+	return builder.String()
+}
+
 func main() {
+	render := flag.Bool("render", false, "Print the maze with the loop and the enclosed tiles marked.")
+	useColor := flag.Bool("color", false, "Highlight the rendered maze with ANSI colors.")
+	method := flag.String("method", "scanline", "The method used to count the enclosed tiles: 'scanline' or 'shoelace'. The scanline method also runs when -render is set, to mark the enclosed tiles.")
+	flag.Parse()
+
+	if *method != "scanline" && *method != "shoelace" {
+		log.Fatalf("Invalid method '%s'. Use 'scanline' or 'shoelace'.", *method)
+	}
+
 	fileLines := utils.LoadFile("input.txt")
 	initializeGraph(&fileLines)
-	computeGraph(&fileLines)
-	fmt.Printf("The number of enclosed tiles is %d.\n", findEnclosedValueCount())
+	loop, err := computeGraph()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var enclosedCount int
+	if *method == "shoelace" {
+		enclosedCount = findEnclosedValueCountByArea(loop.path)
+	} else {
+		enclosedCount = findEnclosedValueCount()
+	}
+
+	if *render {
+		// The rendered maze marks the enclosed tiles, which are only identified
+		// by the scanline method.
+		if *method != "scanline" {
+			findEnclosedValueCount()
+		}
+
+		row, col := findStartingPoint()
+		startType := getNodeType(graph[row][col])
+		fmt.Print(renderMaze(*useColor))
+		fmt.Printf("The starting tile at (%d, %d) is a '%c' (%c) piece.\n", row, col, inputCharacters[startType], boxCharacters[startType])
+	}
+
+	fmt.Printf("The number of enclosed tiles is %d.\n", enclosedCount)
 }
@@ -0,0 +1,114 @@
+package main
+
+import (
+	utils "kqarryzada/advent-of-code-2023/utils"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The number of enclosed tiles in each example file. The first three examples
+// are the loops from part 1, and the others are given in the puzzle.
+var enclosedCounts = map[string]int{
+	"example.txt":  1,
+	"example2.txt": 1,
+	"example3.txt": 1,
+	"example4.txt": 4,
+	"example5.txt": 8,
+	"example6.txt": 10,
+}
+
+// Test_enclosedValueCountMethods verifies that the scanline and shoelace methods
+// both find the expected number of enclosed tiles for every example file.
+func Test_enclosedValueCountMethods(t *testing.T) {
+	files, err := filepath.Glob("example*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("No example files were found.")
+	}
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			fileLines := utils.LoadFile(file)
+			initializeGraph(&fileLines)
+			loop, err := computeGraph()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, ok := enclosedCounts[file]
+			if !ok {
+				t.Fatalf("No expected count is defined for %s.", file)
+			}
+
+			if got := findEnclosedValueCount(); got != want {
+				t.Errorf("findEnclosedValueCount() = %v, want %v", got, want)
+			}
+			if got := findEnclosedValueCountByArea(loop.path); got != want {
+				t.Errorf("findEnclosedValueCountByArea() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_computeGraph(t *testing.T) {
+	fileLines := utils.LoadFile("example.txt")
+	initializeGraph(&fileLines)
+	loop, err := computeGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loop.maxDistance != 4 {
+		t.Errorf("maxDistance = %v, want 4", loop.maxDistance)
+	}
+	if len(loop.farthest) != 1 || loop.farthest[0].row != 3 || loop.farthest[0].col != 3 {
+		t.Errorf("farthest = %v, want the single tile at (3, 3)", loop.farthest)
+	}
+
+	// The loop visits the 8 pipes of the square, beginning at the starting tile.
+	if len(loop.path) != 8 {
+		t.Fatalf("len(path) = %v, want 8", len(loop.path))
+	}
+	if start := loop.path[0]; start.row != 1 || start.col != 1 {
+		t.Errorf("path[0] = (%d, %d), want the starting tile at (1, 1)", start.row, start.col)
+	}
+	for i := 1; i < len(loop.path); i++ {
+		previous, current := loop.path[i-1], loop.path[i]
+		if abs(previous.row-current.row)+abs(previous.col-current.col) != 1 {
+			t.Errorf("path[%d] and path[%d] are not adjacent", i-1, i)
+		}
+	}
+}
+
+func Test_computeGraph_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		grid    []string
+		wantErr string
+	}{
+		{"Start joined to three pipes", []string{".|.", "-S-", "..."}, "connects to 3 pipes"},
+		{"Start joined to four pipes", []string{".|.", "-S-", ".|."}, "connects to 4 pipes"},
+		{"Start joined to one pipe", []string{"...", ".S-", "..."}, "connects to 1 pipes"},
+		{"Loop that does not close", []string{".....", ".S-7.", ".|...", "....."}, "the loop is not closed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initializeGraph(&tt.grid)
+			_, err := computeGraph()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("computeGraph() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// abs returns the absolute value of an integer.
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -1,13 +1,21 @@
 package main
 
 import (
+	"encoding/csv"
+	"flag"
 	"fmt"
+	"io"
 	utils "kqarryzada/advent-of-code-2023/utils"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 )
 
-// This value corresponds to the number of rows/columns that should be inserted
-// in the place of an empty row or column.
-var DISTANCE_MULTIPLIER = 1000000
+// The default number of rows/columns that should be inserted in the place of an
+// empty row or column. Part 1 of the puzzle uses an expansion factor of 2.
+var DEFAULT_EXPANSION_FACTOR = 1000000
 
 type coordinate struct {
 	row int
@@ -15,8 +23,9 @@ type coordinate struct {
 }
 
 // This function parses the input file to extract a list of coordinates that
-// correspond to the locations of galaxies.
-func parse(fileLines []string) []*coordinate {
+// correspond to the locations of galaxies. Each empty row or column is replaced
+// with 'expansionFactor' empty rows or columns.
+func parse(fileLines []string, expansionFactor int) []*coordinate {
 	coordinateList := make([]*coordinate, 0)
 
 	row := 0
@@ -33,13 +42,15 @@ func parse(fileLines []string) []*coordinate {
 
 		if blankLine {
 			// There were no galaxies in this row.
-			row += DISTANCE_MULTIPLIER - 1
+			row += expansionFactor - 1
 		}
 		row++
 	}
 
-	// Assemble a list of blank columns.
-	blankColumnList := make([]int, 0)
+	// Determine the expanded value of each column by counting the number of
+	// blank columns that precede it.
+	expandedColumns := make([]int, len(fileLines[0]))
+	blankColumnCount := 0
 	for j := range fileLines[0] {
 		blankColumn := true
 		for i := range fileLines {
@@ -49,18 +60,14 @@ func parse(fileLines []string) []*coordinate {
 			}
 		}
 		if blankColumn {
-			blankColumnList = append(blankColumnList, j)
+			blankColumnCount++
 		}
+		expandedColumns[j] = j + blankColumnCount*(expansionFactor-1)
 	}
 
 	// Iterate through the assembled coordinates and update the column values.
 	for _, coord := range coordinateList {
-		originalColumn := coord.col
-		for _, blankColumn := range blankColumnList {
-			if originalColumn >= blankColumn {
-				coord.col += DISTANCE_MULTIPLIER - 1
-			}
-		}
+		coord.col = expandedColumns[coord.col]
 	}
 	return coordinateList
 }
@@ -81,21 +88,192 @@ func findDistance(first *coordinate, second *coordinate) int {
 	return xDiff + yDiff
 }
 
-func sumDistances(coordinateList []*coordinate) int {
+// sumAxisDistances computes the sum of the distances between every pair of
+// values along a single axis. Once the values are sorted, each value is larger
+// than all of the values before it, so its contribution to the sum is its value
+// multiplied by the number of preceding values, minus the sum of those values.
+func sumAxisDistances(values []int) int {
+	slices.Sort(values)
+
 	sum := 0
+	prefixSum := 0
+	for i, value := range values {
+		sum += value*i - prefixSum
+		prefixSum += value
+	}
+
+	return sum
+}
+
+// sumDistances computes the sum of the distances between every pair of
+// galaxies. Since the distance between two galaxies is the sum of the distances
+// along each axis, the axes can be summed independently. This requires
+// O(n log n) time rather than comparing every pair of galaxies.
+func sumDistances(coordinateList []*coordinate) int {
+	rows := make([]int, len(coordinateList))
+	cols := make([]int, len(coordinateList))
+	for i, galaxy := range coordinateList {
+		rows[i] = galaxy.row
+		cols[i] = galaxy.col
+	}
+
+	return sumAxisDistances(rows) + sumAxisDistances(cols)
+}
+
+// galaxyPair holds two galaxies, identified by their 1-based numbers in the
+// order that they appear in the input file, and the distance between them.
+type galaxyPair struct {
+	first    int
+	second   int
+	distance int
+}
+
+// galaxyDistance finds the distance between the galaxies with the numbers
+// 'first' and 'second'. Galaxies are numbered from 1, as in the puzzle.
+func galaxyDistance(coordinateList []*coordinate, first int, second int) (int, error) {
+	for _, number := range []int{first, second} {
+		if number < 1 || number > len(coordinateList) {
+			return 0, fmt.Errorf("galaxy %d does not exist, there are %d galaxies", number, len(coordinateList))
+		}
+	}
+
+	return findDistance(coordinateList[first-1], coordinateList[second-1]), nil
+}
+
+// findExtremePairs finds the pair of galaxies that are closest together and the
+// pair that are farthest apart. When multiple pairs share the same distance,
+// the pair that appears first is returned. An error is returned if there are
+// fewer than two galaxies.
+func findExtremePairs(coordinateList []*coordinate) (closest galaxyPair, farthest galaxyPair, err error) {
+	if len(coordinateList) < 2 {
+		return closest, farthest, fmt.Errorf("at least two galaxies are required to form a pair, but the input contains %d", len(coordinateList))
+	}
+
+	closest.distance = -1
+	farthest.distance = -1
 	for i, galaxy := range coordinateList {
 		for j := i + 1; j < len(coordinateList); j++ {
-			nextGalaxy := coordinateList[j]
-			sum += findDistance(galaxy, nextGalaxy)
+			distance := findDistance(galaxy, coordinateList[j])
+			pair := galaxyPair{i + 1, j + 1, distance}
+			if closest.distance == -1 || distance < closest.distance {
+				closest = pair
+			}
+			if distance > farthest.distance {
+				farthest = pair
+			}
 		}
 	}
 
-	return sum
+	return closest, farthest, nil
+}
+
+// writeDistanceMatrix writes the distance between every pair of galaxies as a
+// CSV table. The first row and column contain the galaxy numbers.
+func writeDistanceMatrix(writer io.Writer, coordinateList []*coordinate) error {
+	csvWriter := csv.NewWriter(writer)
+
+	header := make([]string, len(coordinateList)+1)
+	header[0] = "galaxy"
+	for i := range coordinateList {
+		header[i+1] = strconv.Itoa(i + 1)
+	}
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(coordinateList)+1)
+	for i, galaxy := range coordinateList {
+		record[0] = strconv.Itoa(i + 1)
+		for j, otherGalaxy := range coordinateList {
+			record[j+1] = strconv.Itoa(findDistance(galaxy, otherGalaxy))
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// parsePair converts a value such as "5,9" into a pair of galaxy numbers.
+func parsePair(value string) (int, int, error) {
+	fields := strings.Split(value, ",")
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("expected two comma-separated galaxy numbers, but found '%s'", value)
+	}
+
+	first, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid galaxy number '%s'", fields[0])
+	}
+	second, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid galaxy number '%s'", fields[1])
+	}
+
+	return first, second, nil
 }
 
 func main() {
+	expansionFactor := flag.Int("factor", DEFAULT_EXPANSION_FACTOR, "The number of rows or columns that replace each empty row or column.")
+	pairFlag := flag.String("pair", "", "Report the distance between two galaxies, e.g. '5,9'. Galaxies are numbered from 1.")
+	extremes := flag.Bool("extremes", false, "Report the closest and farthest pairs of galaxies.")
+	csvFile := flag.String("csv", "", "Write the distance matrix of all galaxies to a CSV file. Use '-' for standard output.")
+	flag.Parse()
+
+	if *expansionFactor < 1 {
+		log.Fatalf("The expansion factor must be at least 1, but %d was provided.", *expansionFactor)
+	}
+
 	fileLines := utils.LoadFile("input.txt")
-	galaxyLocations := parse(fileLines)
+	galaxyLocations := parse(fileLines, *expansionFactor)
 
-	fmt.Printf("The sum of all distance pairs is %d.\n", sumDistances(galaxyLocations))
+	// When the distance matrix is written to standard output, the other results
+	// are printed to standard error so that the output remains valid CSV.
+	output := os.Stdout
+	if *csvFile == "-" {
+		output = os.Stderr
+	}
+
+	fmt.Fprintf(output, "The sum of all distance pairs is %d.\n", sumDistances(galaxyLocations))
+
+	if *pairFlag != "" {
+		first, second, err := parsePair(*pairFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		distance, err := galaxyDistance(galaxyLocations, first, second)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(output, "The distance between galaxy %d and galaxy %d is %d.\n", first, second, distance)
+	}
+
+	if *extremes {
+		closest, farthest, err := findExtremePairs(galaxyLocations)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(output, "The closest pair is galaxy %d and galaxy %d, with a distance of %d.\n", closest.first, closest.second, closest.distance)
+		fmt.Fprintf(output, "The farthest pair is galaxy %d and galaxy %d, with a distance of %d.\n", farthest.first, farthest.second, farthest.distance)
+	}
+
+	if *csvFile == "-" {
+		if err := writeDistanceMatrix(os.Stdout, galaxyLocations); err != nil {
+			log.Fatal(err)
+		}
+	} else if *csvFile != "" {
+		file, err := os.Create(*csvFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+
+		if err := writeDistanceMatrix(file, galaxyLocations); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(output, "The distance matrix was written to %s.\n", *csvFile)
+	}
 }
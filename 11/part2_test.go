@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	utils "kqarryzada/advent-of-code-2023/utils"
+	"slices"
+	"testing"
+)
+
+func newCoord(row int, col int) *coordinate {
+	return &coordinate{row: row, col: col}
+}
+
+func Test_findDistance(t *testing.T) {
+	type args struct {
+		first  *coordinate
+		second *coordinate
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{"Example 1", args{newCoord(6, 1), newCoord(11, 5)}, 9},
+		{"Example 2", args{newCoord(0, 4), newCoord(10, 9)}, 15},
+		{"Example 3", args{newCoord(2, 0), newCoord(7, 12)}, 17},
+		{"Example 4", args{newCoord(11, 0), newCoord(11, 5)}, 5},
+		{"Example 5", args{newCoord(0, 4), newCoord(1, 9)}, 6},
+		{"Example 6", args{newCoord(0, 4), newCoord(2, 0)}, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findDistance(tt.args.first, tt.args.second); got != tt.want {
+				t.Errorf("findDistance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sumDistances(t *testing.T) {
+	type args struct {
+		expansionFactor int
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{"Part 1 example", args{2}, 374},
+		{"Expansion factor of 10", args{10}, 1030},
+		{"Expansion factor of 100", args{100}, 8410},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileLines := utils.LoadFile("example.txt")
+			galaxies := parse(fileLines, tt.args.expansionFactor)
+			if got := sumDistances(galaxies); got != tt.want {
+				t.Errorf("sumDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_galaxyDistance(t *testing.T) {
+	type args struct {
+		first  int
+		second int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr bool
+	}{
+		{"Galaxies 5 and 9", args{5, 9}, 9, false},
+		{"Galaxies 1 and 7", args{1, 7}, 15, false},
+		{"Galaxies 3 and 6", args{3, 6}, 17, false},
+		{"Galaxies 8 and 9", args{8, 9}, 5, false},
+		{"Same galaxy", args{4, 4}, 0, false},
+		{"Galaxy 0", args{0, 3}, 0, true},
+		{"Galaxy past the end", args{1, 10}, 0, true},
+	}
+	galaxies := parse(utils.LoadFile("example.txt"), 2)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := galaxyDistance(galaxies, tt.args.first, tt.args.second)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("galaxyDistance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("galaxyDistance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findExtremePairs(t *testing.T) {
+	tests := []struct {
+		name         string
+		galaxies     []*coordinate
+		wantClosest  galaxyPair
+		wantFarthest galaxyPair
+		wantErr      bool
+	}{
+		{"Part 1 example", parse(utils.LoadFile("example.txt"), 2), galaxyPair{2, 4, 5}, galaxyPair{2, 8, 19}, false},
+		{"Two galaxies", []*coordinate{newCoord(0, 0), newCoord(3, 4)}, galaxyPair{1, 2, 7}, galaxyPair{1, 2, 7}, false},
+		{"One galaxy", []*coordinate{newCoord(0, 0)}, galaxyPair{}, galaxyPair{}, true},
+		{"No galaxies", []*coordinate{}, galaxyPair{}, galaxyPair{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			closest, farthest, err := findExtremePairs(tt.galaxies)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findExtremePairs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if closest != tt.wantClosest || farthest != tt.wantFarthest {
+				t.Errorf("findExtremePairs() = (%v, %v), want (%v, %v)", closest, farthest, tt.wantClosest, tt.wantFarthest)
+			}
+		})
+	}
+}
+
+func Test_parsePair(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		wantFirst  int
+		wantSecond int
+		wantErr    bool
+	}{
+		{"Valid pair", "5,9", 5, 9, false},
+		{"Spaces", " 1 , 7 ", 1, 7, false},
+		{"Single number", "5", 0, 0, true},
+		{"Three numbers", "1,2,3", 0, 0, true},
+		{"Invalid number", "a,2", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second, err := parsePair(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePair() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if first != tt.wantFirst || second != tt.wantSecond {
+				t.Errorf("parsePair() = (%v, %v), want (%v, %v)", first, second, tt.wantFirst, tt.wantSecond)
+			}
+		})
+	}
+}
+
+func Test_writeDistanceMatrix(t *testing.T) {
+	galaxies := parse(utils.LoadFile("example.txt"), 2)
+
+	var buffer bytes.Buffer
+	if err := writeDistanceMatrix(&buffer, galaxies); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("the distance matrix is not valid CSV: %v", err)
+	}
+
+	wantHeader := []string{"galaxy", "1", "2", "3", "4", "5", "6", "7", "8", "9"}
+	if !slices.Equal(records[0], wantHeader) {
+		t.Errorf("header = %v, want %v", records[0], wantHeader)
+	}
+
+	wantFirstRow := []string{"1", "0", "6", "6", "9", "9", "15", "15", "15", "12"}
+	if !slices.Equal(records[1], wantFirstRow) {
+		t.Errorf("first row = %v, want %v", records[1], wantFirstRow)
+	}
+
+	if len(records) != len(galaxies)+1 {
+		t.Fatalf("found %d records, want %d", len(records), len(galaxies)+1)
+	}
+	for i := 1; i < len(records); i++ {
+		for j := 1; j < len(records); j++ {
+			if records[i][j] != records[j][i] {
+				t.Errorf("the distance matrix is not symmetric at (%d, %d)", i, j)
+			}
+		}
+	}
+}
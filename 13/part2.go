@@ -1,86 +1,132 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	utils "kqarryzada/advent-of-code-2023/utils"
+	"log"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 )
 
-// isAlmostParallel finds the line in a horizontal matrix that would be parallel
-// if one character value was swapped (i.e., a '.' for a '#' or vice versa.)
-func isAlmostParallel(index1 int, index2 int, slice []string) int {
-	i := index1
-	j := index2
-
-	// Check the outer values to ensure symmetry. The unequal strings will hold
-	// the values of of mirrored strings that may be mirrored if a character is
-	// changed.
-	unequalString1 := ""
-	unequalString2 := ""
-	for {
-		if i < 0 || j >= len(slice) {
-			break
+// The default number of smudges on each mirror. Part 1 of the puzzle assumes
+// that the mirrors are clean, while part 2 finds exactly one smudge per mirror.
+var DEFAULT_SMUDGE_COUNT = 1
+
+// A bitLine is a single row or column of a pattern, where each '#' character is
+// stored as a set bit. Multiple words are used so that patterns of any width
+// are supported.
+type bitLine []uint64
+
+// cell is the location of a single character within a pattern.
+type cell struct {
+	row int
+	col int
+}
+
+// toBitLines converts a pattern into its rows and its columns as bit lines.
+func toBitLines(pattern []string) (rows []bitLine, cols []bitLine) {
+	numRows := len(pattern)
+	numCols := len(pattern[0])
+
+	rows = make([]bitLine, numRows)
+	for i := range rows {
+		rows[i] = make(bitLine, (numCols+63)/64)
+	}
+	cols = make([]bitLine, numCols)
+	for j := range cols {
+		cols[j] = make(bitLine, (numRows+63)/64)
+	}
+
+	for i, line := range pattern {
+		if len(line) != numCols {
+			panic("The pattern beginning with '" + pattern[0] + "' is not rectangular.")
 		}
 
-		if slice[i] != slice[j] {
-			if len(unequalString1) == 0 {
-				unequalString1 = slice[i]
-				unequalString2 = slice[j]
-			} else {
-				// A second candidate was found, so these indexes cannot refer
-				// to a reflection.
-				return -1
+		for j, char := range line {
+			switch char {
+			case '#':
+				rows[i][j/64] |= 1 << (j % 64)
+				cols[j][i/64] |= 1 << (i % 64)
+			case '.':
+			default:
+				panic(fmt.Sprintf("Invalid character found: %c", char))
 			}
 		}
+	}
+
+	return rows, cols
+}
 
-		i--
-		j++
+// countDifferences returns the number of positions at which two bit lines have
+// different values.
+func countDifferences(line1 bitLine, line2 bitLine) int {
+	count := 0
+	for k := range line1 {
+		count += bits.OnesCount64(line1[k] ^ line2[k])
 	}
 
-	// When i and j exit the following loop, they will converge to the midway
-	// point.
-	i = index1 + 1
-	j = index2 - 1
-	for {
-		if i >= j {
-			// The midpoint has been reached.
-			break
+	return count
+}
+
+// differingPositions returns the positions at which two bit lines have
+// different values.
+func differingPositions(line1 bitLine, line2 bitLine) []int {
+	positions := make([]int, 0)
+	for k := range line1 {
+		diff := line1[k] ^ line2[k]
+		for diff != 0 {
+			bit := bits.TrailingZeros64(diff)
+			positions = append(positions, k*64+bit)
+			diff &= diff - 1
 		}
+	}
 
-		if slice[i] != slice[j] {
-			if len(unequalString1) == 0 {
-				unequalString1 = slice[i]
-				unequalString2 = slice[j]
-			} else {
-				// A second candidate was found, so these indexes cannot refer
-				// to a reflection.
-				return -1
+	return positions
+}
+
+// findReflections returns every index at which the lines reflect with exactly
+// 'smudgeCount' differing cells. An index refers to the number of lines that
+// appear before the line of reflection.
+func findReflections(lines []bitLine, smudgeCount int) []int {
+	reflections := make([]int, 0)
+	for index := 1; index < len(lines); index++ {
+		differences := 0
+		for i, j := index-1, index; i >= 0 && j < len(lines); i, j = i-1, j+1 {
+			differences += countDifferences(lines[i], lines[j])
+			if differences > smudgeCount {
+				break
 			}
 		}
 
-		i++
-		j--
+		if differences == smudgeCount {
+			reflections = append(reflections, index)
+		}
 	}
 
-	if len(unequalString1) == 0 {
-		// An off-by-one reflection was not found.
-		return -1
-	}
+	return reflections
+}
 
-	// Ensure the two unequal strings have a single differing character.
-	char := byte('0')
-	for k := range unequalString1 {
-		if unequalString1[k] != unequalString2[k] {
-			if char == '0' {
-				char = unequalString1[k]
+// findSmudges returns the cells that must be changed for the lines to reflect
+// perfectly at the provided index. Of each mismatched pair of cells, the cell
+// before the line of reflection is reported. When 'isRow' is false, the lines
+// are treated as the columns of the pattern.
+func findSmudges(lines []bitLine, index int, isRow bool) []cell {
+	smudges := make([]cell, 0)
+	for i, j := index-1, index; i >= 0 && j < len(lines); i, j = i-1, j+1 {
+		for _, position := range differingPositions(lines[i], lines[j]) {
+			if isRow {
+				smudges = append(smudges, cell{i, position})
 			} else {
-				// These strings have more than one character that are
-				// different.
-				return -1
+				smudges = append(smudges, cell{position, i})
 			}
 		}
 	}
 
-	return max(i, j)
+	return smudges
 }
 
 // calculateValue obtains the numerical value of a matrix given the parallel
@@ -94,70 +140,161 @@ func calculateValue(index int, isRow bool) int {
 	return value
 }
 
-// findHorizontalParallelLine finds the prallel line of a matrix that is almost
-// parallel. If no valid line is found, this function returns -1.
-func findHorizontalParallelLine(pattern []string) int {
-	for i := range pattern {
-		for j := i + 1; j < len(pattern); j += 2 {
-			parallelPoint := isAlmostParallel(i, j, pattern)
-			if parallelPoint != -1 {
-				return parallelPoint
-			}
-		}
+// A reflection is a single line of reflection within a pattern.
+type reflection struct {
+	// The number of rows or columns before the line of reflection.
+	index int
+
+	// Indicates whether the line of reflection is horizontal, and therefore
+	// lies between two rows.
+	isRow bool
+
+	// The cells that must be changed for the pattern to reflect perfectly.
+	smudges []cell
+}
+
+// patternResult holds every line of reflection that was found in a pattern.
+type patternResult struct {
+	// The 1-based number of the pattern in the input file.
+	number int
+
+	horizontal []reflection
+	vertical   []reflection
+}
+
+// value obtains the "value" of a pattern as described by the problem. This value
+// is calculated from the number of rows or columns before the line of
+// reflection. If a pattern has multiple lines of reflection, the first
+// horizontal line is preferred.
+func (result *patternResult) value() int {
+	if len(result.horizontal) != 0 {
+		return calculateValue(result.horizontal[0].index, true)
+	}
+	if len(result.vertical) != 0 {
+		return calculateValue(result.vertical[0].index, false)
 	}
 
-	return -1
+	return 0
 }
 
-func invertMatrix(pattern []string) []string {
-	inversePattern := make([]string, 0)
+// analyzePattern finds every line of reflection of a pattern while accounting
+// for exactly 'smudgeCount' smudges on the mirror. An error is returned if the
+// pattern has no line of reflection, though the result is still returned so
+// that it can be included in a report.
+func analyzePattern(pattern []string, number int, smudgeCount int) (*patternResult, error) {
+	rows, cols := toBitLines(pattern)
+	result := &patternResult{number: number}
 
-	for i := 0; i < len(pattern[0]); i++ {
-		inverseString := make([]byte, 0)
-		for j := range pattern {
-			inverseString = append(inverseString, pattern[j][i])
-		}
+	for _, index := range findReflections(rows, smudgeCount) {
+		result.horizontal = append(result.horizontal, reflection{index, true, findSmudges(rows, index, true)})
+	}
+	for _, index := range findReflections(cols, smudgeCount) {
+		result.vertical = append(result.vertical, reflection{index, false, findSmudges(cols, index, false)})
+	}
 
-		inversePattern = append(inversePattern, string(inverseString))
+	if len(result.horizontal) == 0 && len(result.vertical) == 0 {
+		return result, fmt.Errorf("could not find a line of reflection for pattern %d, which begins with: %s", number, pattern[0])
 	}
 
-	return inversePattern
+	return result, nil
 }
 
-// computePattern obtains the "value" of a matrix as described by the problem.
-// This value is calculated from the number of rows or columns to the left of
-// the parallel point (while accounting for a single smudge in the mirror).
-func computePattern(pattern []string) int {
-	isHorizontal := true
-	patternValue := findHorizontalParallelLine(pattern)
-	if patternValue == -1 {
-		isHorizontal = false
-		inversePattern := invertMatrix(pattern)
-		patternValue = findHorizontalParallelLine(inversePattern)
-		if patternValue == -1 {
-			panic("Could not find value for the pattern beginning with: " + pattern[0])
+// formatReflections lists the indexes of the reflections, or '-' if there are
+// none.
+func formatReflections(reflections []reflection) string {
+	if len(reflections) == 0 {
+		return "-"
+	}
+
+	indexes := make([]string, len(reflections))
+	for i, r := range reflections {
+		indexes[i] = strconv.Itoa(r.index)
+	}
+
+	return strings.Join(indexes, ",")
+}
+
+// formatSmudges lists the 1-based (row, column) locations of the smudges of
+// every reflection, or '-' if there are none.
+func formatSmudges(result *patternResult) string {
+	locations := make([]string, 0)
+	for _, reflections := range [][]reflection{result.horizontal, result.vertical} {
+		for _, r := range reflections {
+			for _, smudge := range r.smudges {
+				locations = append(locations, fmt.Sprintf("(%d, %d)", smudge.row+1, smudge.col+1))
+			}
 		}
 	}
 
-	return calculateValue(patternValue, isHorizontal)
+	if len(locations) == 0 {
+		return "-"
+	}
+
+	return strings.Join(locations, " ")
+}
+
+// printSummaryTable prints a table containing the lines of reflection, smudges,
+// and value of each pattern.
+func printSummaryTable(results []*patternResult) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "Pattern\tHorizontal\tVertical\tSmudges\tValue")
+	for _, result := range results {
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%d\n",
+			result.number,
+			formatReflections(result.horizontal),
+			formatReflections(result.vertical),
+			formatSmudges(result),
+			result.value(),
+		)
+	}
+	writer.Flush()
 }
 
 func main() {
+	smudgeCount := flag.Int("smudges", DEFAULT_SMUDGE_COUNT, "The number of smudges on each mirror.")
+	showTable := flag.Bool("table", false, "Print a summary table of the reflections found in each pattern.")
+	flag.Parse()
+
+	if *smudgeCount < 0 {
+		log.Fatalf("The number of smudges cannot be negative, but %d was provided.", *smudgeCount)
+	}
+
 	fileLines := utils.LoadFile("input.txt")
-	sum := 0
+	results := make([]*patternResult, 0)
 	pattern := make([]string, 0)
 
+	// processPattern analyzes the current pattern. Patterns without a line of
+	// reflection are reported, but do not stop the remaining patterns from
+	// being processed.
+	processPattern := func() {
+		result, err := analyzePattern(pattern, len(results)+1, *smudgeCount)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		results = append(results, result)
+	}
+
 	for _, line := range fileLines {
 		if len(line) != 0 {
 			pattern = append(pattern, line)
 		} else {
-			sum += computePattern(pattern)
+			processPattern()
 			pattern = make([]string, 0)
 		}
 	}
 
 	if len(pattern) != 0 {
-		sum += computePattern(pattern)
+		processPattern()
+	}
+
+	if *showTable {
+		printSummaryTable(results)
+		fmt.Println()
+	}
+
+	sum := 0
+	for _, result := range results {
+		sum += result.value()
 	}
 
 	fmt.Printf("The numerical value found from summarizing the notes is %d.\n", sum)
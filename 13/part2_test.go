@@ -0,0 +1,157 @@
+package main
+
+import (
+	utils "kqarryzada/advent-of-code-2023/utils"
+	"strings"
+	"testing"
+)
+
+// loadPatterns splits the lines of an input file into its patterns.
+func loadPatterns(filename string) [][]string {
+	patterns := make([][]string, 0)
+	pattern := make([]string, 0)
+	for _, line := range utils.LoadFile(filename) {
+		if len(line) != 0 {
+			pattern = append(pattern, line)
+		} else {
+			patterns = append(patterns, pattern)
+			pattern = make([]string, 0)
+		}
+	}
+	if len(pattern) != 0 {
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns
+}
+
+func Test_summarize(t *testing.T) {
+	tests := []struct {
+		name        string
+		smudgeCount int
+		want        int
+	}{
+		{"Part 1 example", 0, 405},
+		{"Part 2 example", 1, 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum := 0
+			for i, pattern := range loadPatterns("example.txt") {
+				result, err := analyzePattern(pattern, i+1, tt.smudgeCount)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sum += result.value()
+			}
+
+			if sum != tt.want {
+				t.Errorf("sum of value() = %v, want %v", sum, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findSmudges_example(t *testing.T) {
+	pattern := loadPatterns("example.txt")[0]
+	result, err := analyzePattern(pattern, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := formatReflections(result.horizontal); got != "3" {
+		t.Errorf("horizontal reflections = %v, want 3", got)
+	}
+	if got := formatSmudges(result); got != "(1, 1)" {
+		t.Errorf("formatSmudges() = %v, want (1, 1)", got)
+	}
+}
+
+// Test_findReflections_wide checks a pattern that needs more than one word per
+// row, with the only difference in the second word.
+func Test_findReflections_wide(t *testing.T) {
+	const width = 70
+	first := strings.Repeat("#..#.##...", width/10)
+	second := strings.Repeat(".##...#.#.", width/10)
+	smudged := []byte(second)
+	smudged[65] = '#'
+	pattern := []string{first, second, string(smudged), first}
+
+	rows, _ := toBitLines(pattern)
+	if len(rows[0]) != 2 {
+		t.Fatalf("a row of %d columns uses %d words, want 2", width, len(rows[0]))
+	}
+	if got := countDifferences(rows[1], rows[2]); got != 1 {
+		t.Errorf("countDifferences() = %v, want 1", got)
+	}
+
+	tests := []struct {
+		name        string
+		smudgeCount int
+		want        string
+		wantSmudges string
+	}{
+		{"Without smudges", 0, "-", "-"},
+		{"With one smudge", 1, "2", "(2, 66)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := analyzePattern(pattern, 1, tt.smudgeCount)
+			if got := formatReflections(result.horizontal); got != tt.want {
+				t.Errorf("horizontal reflections = %v, want %v", got, tt.want)
+			}
+			if got := formatSmudges(result); got != tt.wantSmudges {
+				t.Errorf("formatSmudges() = %v, want %v", got, tt.wantSmudges)
+			}
+		})
+	}
+}
+
+func Test_analyzePattern(t *testing.T) {
+	tests := []struct {
+		name           string
+		pattern        []string
+		wantHorizontal string
+		wantVertical   string
+		wantValue      int
+		wantErr        bool
+	}{
+		{"No reflection", []string{"#.", ".."}, "-", "-", 0, true},
+		{"Several reflections", []string{"##", "##", "##"}, "1,2", "1", 100, false},
+		{"Vertical only", []string{"#..#", ".##."}, "-", "2", 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := analyzePattern(tt.pattern, 7, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("analyzePattern() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result == nil || result.number != 7 {
+				t.Fatalf("analyzePattern() = %v, want a result for pattern 7", result)
+			}
+
+			if got := formatReflections(result.horizontal); got != tt.wantHorizontal {
+				t.Errorf("horizontal reflections = %v, want %v", got, tt.wantHorizontal)
+			}
+			if got := formatReflections(result.vertical); got != tt.wantVertical {
+				t.Errorf("vertical reflections = %v, want %v", got, tt.wantVertical)
+			}
+			if got := result.value(); got != tt.wantValue {
+				t.Errorf("value() = %v, want %v", got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func Test_formatSmudges(t *testing.T) {
+	result := &patternResult{
+		horizontal: []reflection{{3, true, []cell{{0, 0}}}},
+		vertical:   []reflection{{5, false, []cell{{2, 4}, {6, 1}}}},
+	}
+	if got, want := formatSmudges(result), "(1, 1) (3, 5) (7, 2)"; got != want {
+		t.Errorf("formatSmudges() = %v, want %v", got, want)
+	}
+	if got := formatSmudges(&patternResult{}); got != "-" {
+		t.Errorf("formatSmudges() = %v, want -", got)
+	}
+}
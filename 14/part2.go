@@ -1,231 +1,293 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	utils "kqarryzada/advent-of-code-2023/utils"
+	"os"
 )
 
 // The number of cycles that were requested.
 var NUM_CYCLES int = 1_000_000_000
 
-// The number of cycles that should be performed before checking for a repeating
-// sequence of matrix cycles.
-var WINDUP_CYCLE_COUNT = 100
+// The sequence of tilts that make up a single spin cycle.
+var DEFAULT_PROGRAM = "NWSE"
 
 // The bounds of the matrix.
 var NUM_ROWS int
 var NUM_COLS int
 
-func slideNorth(inputMatrix *[][]rune) {
-	matrix := *inputMatrix
-
-	for j := range matrix[0] {
-		nextSlot := 0
-		for i := 0; i < NUM_ROWS; i++ {
-			char := matrix[i][j]
+func convertToMatrix(stringLines []string) *[][]rune {
+	matrix := make([][]rune, 0)
+	for _, line := range stringLines {
+		array := []rune(line)
+		matrix = append(matrix, array)
+	}
 
-			if char == '#' {
-				nextSlot = i + 1
-				continue
-			}
+	NUM_ROWS = len(matrix)
+	NUM_COLS = len(matrix[0])
 
-			if char == 'O' {
-				// Swap the two locations if the 'O' character is not already
-				// in the correct position.
-				if nextSlot != i {
-					matrix[nextSlot][j] = 'O'
-					matrix[i][j] = '.'
-				}
-				nextSlot = min(nextSlot+1, NUM_ROWS-1)
-			}
-		}
-	}
+	return &matrix
 }
 
-func slideSouth(inputMatrix *[][]rune) {
-	matrix := *inputMatrix
+// The directions in which the platform can be tilted.
+type direction int
 
-	for j := range matrix[0] {
-		nextSlot := NUM_ROWS - 1
-		for i := (NUM_ROWS - 1); i >= 0; i-- {
-			char := matrix[i][j]
+const (
+	NORTH direction = iota
+	WEST
+	SOUTH
+	EAST
+)
 
-			if char == '#' {
-				nextSlot = i - 1
-				continue
-			}
+var directionNames = [4]string{NORTH: "north", WEST: "west", SOUTH: "south", EAST: "east"}
 
-			if char == 'O' {
-				if nextSlot != i {
-					matrix[nextSlot][j] = 'O'
-					matrix[i][j] = '.'
-				}
-				nextSlot = max(nextSlot-1, 0)
-			}
+func (dir direction) String() string {
+	return directionNames[dir]
+}
+
+// parseDirections converts a string of direction letters, such as "NWSE", into
+// a list of directions.
+func parseDirections(value string) ([]direction, error) {
+	directions := make([]direction, 0, len(value))
+	for i, char := range []rune(value) {
+		switch char {
+		case 'N', 'n':
+			directions = append(directions, NORTH)
+		case 'W', 'w':
+			directions = append(directions, WEST)
+		case 'S', 's':
+			directions = append(directions, SOUTH)
+		case 'E', 'e':
+			directions = append(directions, EAST)
+		default:
+			return nil, fmt.Errorf("invalid direction '%c' at position %d of '%s'", char, i+1, value)
 		}
 	}
+
+	if len(directions) == 0 {
+		return nil, fmt.Errorf("at least one direction must be provided")
+	}
+
+	return directions, nil
 }
 
-func slideWest(inputMatrix *[][]rune) {
-	matrix := *inputMatrix
+// A platform holds the precomputed layout of the cube-shaped rocks ('#'). The
+// cube rocks split every column and row into segments, and a rounded rock ('O')
+// always comes to rest at the edge of its segment when the platform is tilted.
+// This allows a tilt to be computed by counting the rocks in each segment,
+// rather than moving each rock one cell at a time.
+//
+// Cells are identified by their index, which is (row * NUM_COLS + col).
+type platform struct {
+	// For each direction, the segment that each cell belongs to.
+	segmentOf [4][]int
+
+	// For each direction, the cell in each segment where the first rock comes
+	// to rest.
+	segmentStart [4][]int
+
+	// For each direction, the offset between consecutive resting cells within a
+	// segment. For example, rocks stack downward from the top of a segment when
+	// the platform is tilted north.
+	step [4]int
+
+	// The sequence of tilts performed by a single spin.
+	program []direction
+
+	// Scratch space for counting the rocks in each segment, and for tracking
+	// which segments contain rocks.
+	counts  []int
+	touched []int
+}
 
-	for i := range matrix {
-		nextSlot := 0
-		for j := 0; j < NUM_COLS; j++ {
-			char := matrix[i][j]
+// newPlatform computes the segments of the matrix for each direction. The
+// rounded rocks of the matrix are returned as a list of cell indexes. Each spin
+// of the platform performs the tilts listed in 'program'.
+func newPlatform(matrix [][]rune, program []direction) (*platform, []int) {
+	p := new(platform)
+	p.program = program
+	numCells := NUM_ROWS * NUM_COLS
+	rocks := make([]int, 0)
 
+	isCube := make([]bool, numCells)
+	for i, row := range matrix {
+		for j, char := range row {
+			cell := i*NUM_COLS + j
 			if char == '#' {
-				nextSlot = j + 1
-				continue
-			}
-
-			if char == 'O' {
-				if nextSlot != j {
-					matrix[i][nextSlot] = 'O'
-					matrix[i][j] = '.'
-				}
-				nextSlot = min(nextSlot+1, NUM_COLS-1)
+				isCube[cell] = true
+			} else if char == 'O' {
+				rocks = append(rocks, cell)
 			}
 		}
 	}
-}
 
-func slideEast(inputMatrix *[][]rune) {
-	matrix := *inputMatrix
-
-	for i := range matrix {
-		nextSlot := NUM_COLS - 1
-		for j := (NUM_COLS - 1); j >= 0; j-- {
-			char := matrix[i][j]
+	// Each direction walks every line of the matrix, starting from the wall that
+	// the rocks will slide towards.
+	type lineWalk struct {
+		lineCount int
+		lineStart func(line int) int
+		step      int
+		length    int
+	}
+	walks := [4]lineWalk{
+		NORTH: {NUM_COLS, func(j int) int { return j }, NUM_COLS, NUM_ROWS},
+		WEST:  {NUM_ROWS, func(i int) int { return i * NUM_COLS }, 1, NUM_COLS},
+		SOUTH: {NUM_COLS, func(j int) int { return (NUM_ROWS-1)*NUM_COLS + j }, -NUM_COLS, NUM_ROWS},
+		EAST:  {NUM_ROWS, func(i int) int { return i*NUM_COLS + NUM_COLS - 1 }, -1, NUM_COLS},
+	}
 
-			if char == '#' {
-				nextSlot = j - 1
-				continue
-			}
+	maxSegments := 0
+	for dir, walk := range walks {
+		p.step[dir] = walk.step
+		p.segmentOf[dir] = make([]int, numCells)
+		p.segmentStart[dir] = make([]int, 0)
+
+		for line := 0; line < walk.lineCount; line++ {
+			isNewSegment := true
+			cell := walk.lineStart(line)
+			for k := 0; k < walk.length; k, cell = k+1, cell+walk.step {
+				if isCube[cell] {
+					isNewSegment = true
+					continue
+				}
 
-			if char == 'O' {
-				if nextSlot != j {
-					matrix[i][nextSlot] = 'O'
-					matrix[i][j] = '.'
+				if isNewSegment {
+					p.segmentStart[dir] = append(p.segmentStart[dir], cell)
+					isNewSegment = false
 				}
-				nextSlot = max(nextSlot-1, 0)
+				p.segmentOf[dir][cell] = len(p.segmentStart[dir]) - 1
 			}
 		}
+
+		maxSegments = max(maxSegments, len(p.segmentStart[dir]))
 	}
-}
 
-// cycle performs a full iteration of sliding the 'O' values around the matrix.
-func cycle(matrix *[][]rune) {
-	slideNorth(matrix)
-	slideWest(matrix)
-	slideSouth(matrix)
-	slideEast(matrix)
+	p.counts = make([]int, maxSegments)
+	p.touched = make([]int, 0, maxSegments)
+	return p, rocks
 }
 
-func convertToMatrix(stringLines []string) *[][]rune {
-	matrix := make([][]rune, 0)
-	for _, line := range stringLines {
-		array := []rune(line)
-		matrix = append(matrix, array)
+// tilt computes the positions of the rocks after the platform has been tilted in
+// the given direction, and stores them in 'newRocks', which must have the same
+// length as 'rocks'. This requires time proportional to the number of rocks.
+func (p *platform) tilt(rocks []int, newRocks []int, dir direction) {
+	segmentOf := p.segmentOf[dir]
+	touched := p.touched[:0]
+	for _, cell := range rocks {
+		segment := segmentOf[cell]
+		if p.counts[segment] == 0 {
+			touched = append(touched, segment)
+		}
+		p.counts[segment]++
 	}
 
-	NUM_ROWS = len(matrix)
-	NUM_COLS = len(matrix[0])
-
-	return &matrix
-}
-
-// For a given state in the matrix, calculateMatrixLoad computes the numerical
-// "load" of the matrix, which is dependent on the location of 'O' characters.
-func calculateMatrixLoad(inputMatrix *[][]rune) int {
-	matrix := *inputMatrix
-	matrixLength := len(matrix)
-	load := 0
-	for j := range matrix[0] {
-		for i := 0; i < matrixLength; i++ {
-			if matrix[i][j] == 'O' {
-				load += matrixLength - i
-			}
+	index := 0
+	step := p.step[dir]
+	for _, segment := range touched {
+		cell := p.segmentStart[dir][segment]
+		for k := 0; k < p.counts[segment]; k++ {
+			newRocks[index] = cell
+			index++
+			cell += step
 		}
+		p.counts[segment] = 0
 	}
 
-	return load
+	p.touched = touched
 }
 
-func determineCycleCount(inputMatrix *[][]rune) int {
-	// Copy the input matrix to avoid modifications to the input matrix.
-	origMatrix := *inputMatrix
-	matrix := make([][]rune, 0)
-	for i := range origMatrix {
-		row := make([]rune, 0)
-		for j := 0; j < len(origMatrix[0]); j++ {
-			row = append(row, origMatrix[i][j])
-		}
-		matrix = append(matrix, row)
+// spin returns the positions of the rocks after the platform has been tilted in
+// each direction of its program. The input slice is not modified.
+func (p *platform) spin(rocks []int) []int {
+	current := make([]int, len(rocks))
+	next := make([]int, len(rocks))
+	copy(current, rocks)
+	for _, dir := range p.program {
+		p.tilt(current, next, dir)
+		current, next = next, current
 	}
 
-	// Cycle the matrix for several iterations so that the operations reach a
-	// repeating sequence.
-	for i := 0; i < WINDUP_CYCLE_COUNT; i++ {
-		cycle(&matrix)
-	}
+	return current
+}
 
-	matrixCopy := make([]string, len(matrix))
-	for i, row := range matrix {
-		matrixCopy[i] = string(row)
+// rocksKey converts the positions of the rocks into a bitset string, which
+// identifies the state regardless of the order of the rocks in the slice.
+func rocksKey(rocks []int) string {
+	bits := make([]byte, (NUM_ROWS*NUM_COLS+7)/8)
+	for _, cell := range rocks {
+		bits[cell/8] |= 1 << (cell % 8)
 	}
 
-	cycleCount := 0
-	for {
-		cycle(&matrix)
-		cycleCount++
-
-		isEqual := true
-		for i, row := range matrix {
-			if string(row) != matrixCopy[i] {
-				isEqual = false
-				break
-			}
-		}
+	return string(bits)
+}
 
-		if isEqual {
-			break
-		} else if cycleCount >= 100_000 {
-			panic("A repeating sequence was not found after 100,000 iterations. Consider increasing WINDUP_CYCLE_COUNT.")
+// calculateRocksLoad computes the load on the support beams along the given
+// side of the platform. Each rock contributes its distance from the opposite
+// side, so that a rock resting against the given side contributes the most.
+func calculateRocksLoad(rocks []int, side direction) int {
+	load := 0
+	for _, cell := range rocks {
+		row := cell / NUM_COLS
+		col := cell % NUM_COLS
+
+		switch side {
+		case NORTH:
+			load += NUM_ROWS - row
+		case SOUTH:
+			load += row + 1
+		case WEST:
+			load += NUM_COLS - col
+		case EAST:
+			load += col + 1
+		default:
+			panic("Invalid direction entered.")
 		}
 	}
 
-	return cycleCount
+	return load
 }
 
 func main() {
+	programFlag := flag.String("program", DEFAULT_PROGRAM, "The directions tilted during a single spin cycle, e.g. 'NWSE' or 'NNE'.")
+	cyclesFlag := flag.Int("cycles", NUM_CYCLES, "The number of spin cycles to perform.")
+	loadFlag := flag.String("load", "N", "The sides of the platform to report the load on, e.g. 'N' or 'NWSE'.")
+	flag.Parse()
+
+	program, err := parseDirections(*programFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid spin program: %v\n", err)
+		os.Exit(1)
+	}
+
+	loadSides, err := parseDirections(*loadFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid load sides: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *cyclesFlag < 0 {
+		fmt.Fprintln(os.Stderr, "The number of cycles cannot be negative.")
+		os.Exit(1)
+	}
+
 	fileLines := utils.LoadFile("input.txt")
 	matrix := convertToMatrix(fileLines)
+	p, rocks := newPlatform(*matrix, program)
 
-	// Due to the nature of repeatedly sliding elements in 4 directions, there
-	// comes a point where the sequence will repeat since there are only so many
-	// possible states. Once the cycling begins to loop, we should determine the
-	// number of steps required to reach the same state again.
+	// Due to the nature of repeatedly sliding elements in several directions,
+	// there comes a point where the sequence will repeat since there are only so
+	// many possible states. Once the cycling begins to loop, the state after the
+	// requested number of cycles can be determined from its position within the
+	// loop.
 	//
 	// The purpose behind this optimization is to cut down the number of
-	// iterations that this program is required to perform when the value of
-	// NUM_CYCLES is very large.
-	cycleCount := determineCycleCount(matrix)
-
-	// Determining the cycle count requires some "wind up" time to allow the
-	// sequence to reach a repeatable point. Wind up the matrix with the initial
-	// cycle count so that future calls to cycle() are guaranteed to repeat.
-	for i := 0; i < WINDUP_CYCLE_COUNT; i++ {
-		cycle(matrix)
-	}
+	// iterations that this program is required to perform when the number of
+	// cycles is very large.
+	cycleInfo := utils.FindCycle(rocks, p.spin, rocksKey)
+	finalRocks := cycleInfo.StateAt(*cyclesFlag)
 
-	// We have already performed WINDUP_CYCLE_COUNT iterations, so this can be
-	// deducted from the overall NUM_CYCLES count. The cycling should now be a
-	// repeatable process.
-	numIterations := (NUM_CYCLES - WINDUP_CYCLE_COUNT) % cycleCount
-	for i := 0; i < numIterations; i++ {
-		cycle(matrix)
+	for _, side := range loadSides {
+		fmt.Printf("The total load on the %s support beams is %d.\n", side, calculateRocksLoad(finalRocks, side))
 	}
-
-	fmt.Printf("The total load on the support beams is %d.\n", calculateMatrixLoad(matrix))
 }
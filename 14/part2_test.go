@@ -0,0 +1,362 @@
+package main
+
+import (
+	utils "kqarryzada/advent-of-code-2023/utils"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// slideNorth moves the 'O' characters one cell at a time towards the top of the
+// matrix. The slide functions serve as a straightforward reference for the
+// platform tilting.
+func slideNorth(inputMatrix *[][]rune) {
+	matrix := *inputMatrix
+
+	for j := range matrix[0] {
+		nextSlot := 0
+		for i := 0; i < NUM_ROWS; i++ {
+			char := matrix[i][j]
+
+			if char == '#' {
+				nextSlot = i + 1
+				continue
+			}
+
+			if char == 'O' {
+				// Swap the two locations if the 'O' character is not already
+				// in the correct position.
+				if nextSlot != i {
+					matrix[nextSlot][j] = 'O'
+					matrix[i][j] = '.'
+				}
+				nextSlot = min(nextSlot+1, NUM_ROWS-1)
+			}
+		}
+	}
+}
+
+func slideSouth(inputMatrix *[][]rune) {
+	matrix := *inputMatrix
+
+	for j := range matrix[0] {
+		nextSlot := NUM_ROWS - 1
+		for i := (NUM_ROWS - 1); i >= 0; i-- {
+			char := matrix[i][j]
+
+			if char == '#' {
+				nextSlot = i - 1
+				continue
+			}
+
+			if char == 'O' {
+				if nextSlot != i {
+					matrix[nextSlot][j] = 'O'
+					matrix[i][j] = '.'
+				}
+				nextSlot = max(nextSlot-1, 0)
+			}
+		}
+	}
+}
+
+func slideWest(inputMatrix *[][]rune) {
+	matrix := *inputMatrix
+
+	for i := range matrix {
+		nextSlot := 0
+		for j := 0; j < NUM_COLS; j++ {
+			char := matrix[i][j]
+
+			if char == '#' {
+				nextSlot = j + 1
+				continue
+			}
+
+			if char == 'O' {
+				if nextSlot != j {
+					matrix[i][nextSlot] = 'O'
+					matrix[i][j] = '.'
+				}
+				nextSlot = min(nextSlot+1, NUM_COLS-1)
+			}
+		}
+	}
+}
+
+func slideEast(inputMatrix *[][]rune) {
+	matrix := *inputMatrix
+
+	for i := range matrix {
+		nextSlot := NUM_COLS - 1
+		for j := (NUM_COLS - 1); j >= 0; j-- {
+			char := matrix[i][j]
+
+			if char == '#' {
+				nextSlot = j - 1
+				continue
+			}
+
+			if char == 'O' {
+				if nextSlot != j {
+					matrix[i][nextSlot] = 'O'
+					matrix[i][j] = '.'
+				}
+				nextSlot = max(nextSlot-1, 0)
+			}
+		}
+	}
+}
+
+// cycle performs a full iteration of sliding the 'O' values around the matrix.
+func cycle(matrix *[][]rune) {
+	slideNorth(matrix)
+	slideWest(matrix)
+	slideSouth(matrix)
+	slideEast(matrix)
+}
+
+// For a given state in the matrix, calculateMatrixLoad computes the numerical
+// "load" of the matrix, which is dependent on the location of 'O' characters.
+func calculateMatrixLoad(inputMatrix *[][]rune) int {
+	matrix := *inputMatrix
+	matrixLength := len(matrix)
+	load := 0
+	for j := range matrix[0] {
+		for i := 0; i < matrixLength; i++ {
+			if matrix[i][j] == 'O' {
+				load += matrixLength - i
+			}
+		}
+	}
+
+	return load
+}
+
+// randomMatrix generates a square platform with a fixed seed so that the tests
+// and benchmarks are reproducible.
+func randomMatrix(size int) *[][]rune {
+	random := rand.New(rand.NewSource(14))
+	lines := make([]string, size)
+	for i := range lines {
+		line := make([]rune, size)
+		for j := range line {
+			switch value := random.Intn(10); {
+			case value < 2:
+				line[j] = '#'
+			case value < 5:
+				line[j] = 'O'
+			default:
+				line[j] = '.'
+			}
+		}
+		lines[i] = string(line)
+	}
+
+	return convertToMatrix(lines)
+}
+
+// matrixRocks lists the cell indexes of the 'O' characters in the matrix.
+func matrixRocks(matrix [][]rune) []int {
+	rocks := make([]int, 0)
+	for i, row := range matrix {
+		for j, char := range row {
+			if char == 'O' {
+				rocks = append(rocks, i*NUM_COLS+j)
+			}
+		}
+	}
+
+	return rocks
+}
+
+func Test_platformSpin(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{"Single cell", 1},
+		{"Small platform", 10},
+		{"Large platform", 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matrix := randomMatrix(tt.size)
+			p, rocks := newPlatform(*matrix, []direction{NORTH, WEST, SOUTH, EAST})
+			for i := 0; i < 20; i++ {
+				cycle(matrix)
+				rocks = p.spin(rocks)
+
+				want := matrixRocks(*matrix)
+				if rocksKey(rocks) != rocksKey(want) {
+					t.Fatalf("spin() differs from cycle() after %d cycles", i+1)
+				}
+				if got, want := calculateRocksLoad(rocks, NORTH), calculateMatrixLoad(matrix); got != want {
+					t.Fatalf("calculateRocksLoad() = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func Benchmark_cycle(b *testing.B) {
+	matrix := randomMatrix(100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cycle(matrix)
+	}
+}
+
+func Benchmark_platformSpin(b *testing.B) {
+	matrix := randomMatrix(100)
+	p, rocks := newPlatform(*matrix, []direction{NORTH, WEST, SOUTH, EAST})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rocks = p.spin(rocks)
+	}
+}
+
+// referenceSlides maps each direction to the slide function that tilts the
+// matrix in that direction.
+var referenceSlides = map[direction]func(*[][]rune){
+	NORTH: slideNorth,
+	WEST:  slideWest,
+	SOUTH: slideSouth,
+	EAST:  slideEast,
+}
+
+func Test_platformSpin_programs(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+	}{
+		{"North only", "N"},
+		{"Repeated tilt", "NNE"},
+		{"Two directions", "SE"},
+		{"Reversed cycle", "ESWN"},
+		{"Long program", "WWNSEEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := parseDirections(tt.program)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			matrix := randomMatrix(20)
+			p, rocks := newPlatform(*matrix, program)
+			for i := 0; i < 5; i++ {
+				for _, dir := range program {
+					referenceSlides[dir](matrix)
+				}
+				rocks = p.spin(rocks)
+
+				if rocksKey(rocks) != rocksKey(matrixRocks(*matrix)) {
+					t.Fatalf("spin() differs from the slide functions after %d spins", i+1)
+				}
+			}
+		})
+	}
+}
+
+func Test_parseDirections(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []direction
+		wantErr string
+	}{
+		{"Default program", "NWSE", []direction{NORTH, WEST, SOUTH, EAST}, ""},
+		{"Lowercase letters", "nnE", []direction{NORTH, NORTH, EAST}, ""},
+		{"Empty program", "", nil, "at least one direction"},
+		{"Invalid letter", "NXS", nil, "invalid direction 'X' at position 2"},
+		{"Non-ASCII letter", "NNé", nil, "invalid direction 'é' at position 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDirections(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseDirections() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseDirections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_calculateRocksLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		side  direction
+		want  int
+	}{
+		{"Single rock north", []string{"O..", "..."}, NORTH, 2},
+		{"Single rock south", []string{"O..", "..."}, SOUTH, 1},
+		{"Single rock west", []string{"O..", "..."}, WEST, 3},
+		{"Single rock east", []string{"O..", "..."}, EAST, 1},
+		{"Several rocks north", []string{"OO.", "..O"}, NORTH, 5},
+		{"Several rocks south", []string{"OO.", "..O"}, SOUTH, 4},
+		{"Several rocks west", []string{"OO.", "..O"}, WEST, 6},
+		{"Several rocks east", []string{"OO.", "..O"}, EAST, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matrix := convertToMatrix(tt.lines)
+			if got := calculateRocksLoad(matrixRocks(*matrix), tt.side); got != tt.want {
+				t.Errorf("calculateRocksLoad() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_calculateRocksLoad_example checks the load on each side of the example
+// after tilting the platform towards that side, which the puzzle gives as 136
+// for the north side. Since the example is not symmetric, the reference load
+// for the other sides is computed by rotating the matrix.
+func Test_calculateRocksLoad_example(t *testing.T) {
+	for _, side := range []direction{NORTH, WEST, SOUTH, EAST} {
+		t.Run(side.String(), func(t *testing.T) {
+			matrix := convertToMatrix(utils.LoadFile("example.txt"))
+			p, rocks := newPlatform(*matrix, []direction{side})
+			rocks = p.spin(rocks)
+
+			referenceSlides[side](matrix)
+			want := referenceLoad(*matrix, side)
+			if got := calculateRocksLoad(rocks, side); got != want {
+				t.Errorf("calculateRocksLoad() = %v, want %v", got, want)
+			}
+			if side == NORTH && want != 136 {
+				t.Errorf("the north load is %v, want 136", want)
+			}
+		})
+	}
+}
+
+// referenceLoad computes the load along one side of the matrix by rotating the
+// matrix until that side is at the top, and then using calculateMatrixLoad.
+func referenceLoad(matrix [][]rune, side direction) int {
+	rotations := map[direction]int{NORTH: 0, EAST: 1, SOUTH: 2, WEST: 3}[side]
+	for k := 0; k < rotations; k++ {
+		// Rotate counterclockwise, so that the east side becomes the top.
+		rotated := make([][]rune, len(matrix[0]))
+		for i := range rotated {
+			rotated[i] = make([]rune, len(matrix))
+			for j := range rotated[i] {
+				rotated[i][j] = matrix[j][len(matrix[0])-1-i]
+			}
+		}
+		matrix = rotated
+	}
+
+	return calculateMatrixLoad(&matrix)
+}
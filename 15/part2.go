@@ -1,8 +1,13 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	utils "kqarryzada/advent-of-code-2023/utils"
+	"log"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -20,12 +25,32 @@ const (
 	REMOVE
 )
 
-func sequenceHash(sequence string) int {
+// hashFunction holds the parameters of the HASH algorithm. The puzzle uses a
+// multiplier of 17 and a modulus of 256, but other values can be provided to
+// explore the quality of the hash.
+type hashFunction struct {
+	multiplier int
+	modulus    int
+}
+
+var DEFAULT_HASH = hashFunction{multiplier: 17, modulus: 256}
+
+// The largest supported modulus. A box is allocated for every possible hash,
+// so the modulus is limited to keep the hashmap a reasonable size.
+var MAX_MODULUS = 1 << 24
+
+// hash runs the HASH algorithm on a string. The multiplier and each character
+// are reduced by the modulus before they are used, which leaves the result
+// unchanged but keeps every product below (2 * MAX_MODULUS)^2, so that large
+// multipliers cannot overflow.
+func (h hashFunction) hash(sequence string) int {
+	multiplier := h.multiplier % h.modulus
+
 	hash := 0
 	for _, char := range sequence {
-		hash += int(char)
-		hash *= 17
-		hash %= 256
+		hash += int(char) % h.modulus
+		hash *= multiplier
+		hash %= h.modulus
 	}
 
 	return hash
@@ -61,93 +86,254 @@ func parseOperations(stringOperations []string) []operation {
 	return operations
 }
 
-// removeLensFromBox removes an operation from a slice. If the requested
-// operation label is not present in the slice, the original slice is returned.
-func removeLensFromBox(label string, box []operation) []operation {
-	requestedLensIndex := -1
-	for i, lens := range box {
-		if lens.label == label {
-			requestedLensIndex = i
-			break
-		}
+func (op operation) String() string {
+	if op.opType == REMOVE {
+		return op.label + "-"
+	}
+
+	return op.label + "=" + strconv.Itoa(op.focalLength)
+}
+
+// A lens is an entry within a box. Each box links its lenses together in the
+// order that they were inserted.
+type lens struct {
+	label       string
+	focalLength int
+
+	previous *lens
+	next     *lens
+}
+
+// A box is a doubly-linked list of lenses, which allows a lens to be removed
+// without shifting the lenses behind it.
+type box struct {
+	first *lens
+	last  *lens
+	size  int
+}
+
+// append places a lens at the back of the box.
+func (b *box) append(newLens *lens) {
+	newLens.previous = b.last
+	newLens.next = nil
+	if b.last == nil {
+		b.first = newLens
+	} else {
+		b.last.next = newLens
 	}
+	b.last = newLens
+	b.size++
+}
 
-	if requestedLensIndex == -1 {
-		return box
+// unlink removes a lens from the box, preserving the order of the other lenses.
+func (b *box) unlink(oldLens *lens) {
+	if oldLens.previous == nil {
+		b.first = oldLens.next
+	} else {
+		oldLens.previous.next = oldLens.next
 	}
 
-	if requestedLensIndex == 0 {
-		return box[1:]
+	if oldLens.next == nil {
+		b.last = oldLens.previous
+	} else {
+		oldLens.next.previous = oldLens.previous
 	}
 
-	if requestedLensIndex == len(box)-1 {
-		return box[:len(box)-1]
+	oldLens.previous = nil
+	oldLens.next = nil
+	b.size--
+}
+
+// lensHashMap is the HASHMAP described by the puzzle. Lenses are placed in the
+// box given by the hash of their label, and each box keeps its lenses in
+// insertion order. An index from each label to its lens allows lenses to be
+// replaced or removed in constant time, rather than searching through the box.
+type lensHashMap struct {
+	hashFunc hashFunction
+	boxes    []box
+	index    map[string]*lens
+
+	// The largest number of lenses that a single box has held at once.
+	longestChain int
+}
+
+func newLensHashMap(hashFunc hashFunction) *lensHashMap {
+	return &lensHashMap{
+		hashFunc: hashFunc,
+		boxes:    make([]box, hashFunc.modulus),
+		index:    make(map[string]*lens),
+	}
+}
+
+// put inserts a lens at the back of its box. If a lens with the same label is
+// already present, its focal length is replaced and it keeps its position.
+func (m *lensHashMap) put(label string, focalLength int) {
+	if existing, ok := m.index[label]; ok {
+		existing.focalLength = focalLength
+		return
 	}
 
-	return append(box[:requestedLensIndex], box[requestedLensIndex+1:]...)
+	newLens := &lens{label: label, focalLength: focalLength}
+	b := &m.boxes[m.hashFunc.hash(label)]
+	b.append(newLens)
+	m.index[label] = newLens
+	m.longestChain = max(m.longestChain, b.size)
 }
 
-// replaceLensInBox replaces an labelled operation within a slice. If the
-// requested operation label is not present, it will be appended to the end of
-// the lice.
-func replaceLensInBox(op operation, box []operation) []operation {
-	requestedLensIndex := -1
-	for i, lens := range box {
-		if lens.label == op.label {
-			requestedLensIndex = i
-			break
+// remove takes the lens with the given label out of its box. Nothing happens if
+// the label is not present.
+func (m *lensHashMap) remove(label string) {
+	existing, ok := m.index[label]
+	if !ok {
+		return
+	}
+
+	m.boxes[m.hashFunc.hash(label)].unlink(existing)
+	delete(m.index, label)
+}
+
+// apply performs a single step of the initialization sequence.
+func (m *lensHashMap) apply(op operation) {
+	if op.opType == REMOVE {
+		m.remove(op.label)
+	} else {
+		m.put(op.label, op.focalLength)
+	}
+}
+
+// focusingPower computes the sum of the focusing power of every lens.
+func (m *lensHashMap) focusingPower() int {
+	sum := 0
+	for i, b := range m.boxes {
+		lensNumber := 1
+		for l := b.first; l != nil; l = l.next {
+			boxNumber := i + 1
+			sum += boxNumber * lensNumber * l.focalLength
+			lensNumber++
 		}
 	}
 
-	if requestedLensIndex == -1 {
-		return append(box, op)
+	return sum
+}
+
+// String lists the contents of every non-empty box in the same format as the
+// walkthrough in the puzzle description.
+func (m *lensHashMap) String() string {
+	var builder strings.Builder
+	for i, b := range m.boxes {
+		if b.first == nil {
+			continue
+		}
+
+		fmt.Fprintf(&builder, "Box %d:", i)
+		for l := b.first; l != nil; l = l.next {
+			fmt.Fprintf(&builder, " [%s %d]", l.label, l.focalLength)
+		}
+		builder.WriteRune('\n')
 	}
 
-	box[requestedLensIndex] = op
-	return box
+	return builder.String()
 }
 
-// process consumes a list of operations and arranges them as a list of boxes,
-// where each box contains a list of lenses.
-func process(operations []operation) [][]operation {
-	// The boxList stores the arrangement of lenses within the 256 boxes. Since
-	// the data we need in the boxes is already contained in the operations,
-	// each box holds a slice of operations.
-	boxList := make([][]operation, 256)
+// hashStatistics summarizes how the labels of an initialization sequence are
+// distributed among the boxes.
+type hashStatistics struct {
+	// The number of distinct labels in the sequence, and the number of boxes
+	// that at least one of those labels hashes to.
+	labelCount   int
+	boxesUsed    int
+	boxCount     int
+	longestChain int
 
-	for _, op := range operations {
-		boxID := sequenceHash(op.label)
-		box := boxList[boxID]
+	// The number of distinct labels that hash to a box which is already used by
+	// another label.
+	collisions int
 
-		var newLensOrder []operation
-		if op.opType == REMOVE {
-			newLensOrder = removeLensFromBox(op.label, box)
-		} else {
-			newLensOrder = replaceLensInBox(op, box)
+	// Maps a number of lenses to the number of boxes holding that many lenses
+	// once the sequence is complete.
+	occupancy map[int]int
+}
+
+// computeStatistics gathers the statistics of a hashmap after the provided
+// operations have been applied to it.
+func computeStatistics(hashMap *lensHashMap, operations []operation) *hashStatistics {
+	stats := &hashStatistics{
+		boxCount:     len(hashMap.boxes),
+		longestChain: hashMap.longestChain,
+		occupancy:    make(map[int]int),
+	}
+
+	labels := make(map[string]bool)
+	usedBoxes := make(map[int]bool)
+	for _, op := range operations {
+		if labels[op.label] {
+			continue
 		}
-		boxList[boxID] = newLensOrder
+		labels[op.label] = true
+		usedBoxes[hashMap.hashFunc.hash(op.label)] = true
+	}
+	stats.labelCount = len(labels)
+	stats.boxesUsed = len(usedBoxes)
+	stats.collisions = stats.labelCount - stats.boxesUsed
+
+	for _, b := range hashMap.boxes {
+		stats.occupancy[b.size]++
 	}
 
-	return boxList
+	return stats
 }
 
-func run(operations []operation) int {
-	boxList := process(operations)
+func (stats *hashStatistics) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Distinct labels: %d\n", stats.labelCount)
+	fmt.Fprintf(&builder, "Boxes used by the labels: %d of %d\n", stats.boxesUsed, stats.boxCount)
+	fmt.Fprintf(&builder, "Collisions: %d\n", stats.collisions)
+	fmt.Fprintf(&builder, "Longest chain: %d\n", stats.longestChain)
+	fmt.Fprintln(&builder, "Final box occupancy:")
 
-	sum := 0
-	for i, lensList := range boxList {
-		for j, lens := range lensList {
-			boxNumber := i + 1
-			lensNumber := j + 1
-			focusingPower := boxNumber * lensNumber * lens.focalLength
-			sum += focusingPower
+	sizes := make([]int, 0, len(stats.occupancy))
+	for size := range stats.occupancy {
+		sizes = append(sizes, size)
+	}
+	slices.Sort(sizes)
+	for _, size := range sizes {
+		fmt.Fprintf(&builder, "  %d lenses: %d boxes\n", size, stats.occupancy[size])
+	}
+
+	return builder.String()
+}
+
+// run performs every step of the initialization sequence and returns the
+// resulting hashmap. If 'trace' is not nil, the contents of the boxes are
+// written to it after each step.
+func run(operations []operation, hashFunc hashFunction, trace io.Writer) *lensHashMap {
+	hashMap := newLensHashMap(hashFunc)
+	for _, op := range operations {
+		hashMap.apply(op)
+
+		if trace != nil {
+			fmt.Fprintf(trace, "After \"%s\":\n%s\n", op, hashMap)
 		}
 	}
 
-	return sum
+	return hashMap
 }
 
 func main() {
+	trace := flag.Bool("trace", false, "Print the contents of the boxes after each step.")
+	multiplier := flag.Int("multiplier", DEFAULT_HASH.multiplier, "The multiplier used by the HASH algorithm.")
+	modulus := flag.Int("modulus", DEFAULT_HASH.modulus, "The modulus used by the HASH algorithm, which is also the number of boxes.")
+	showStats := flag.Bool("stats", false, "Print statistics about how the labels are distributed among the boxes.")
+	flag.Parse()
+
+	if *modulus < 1 || *modulus > MAX_MODULUS {
+		log.Fatalf("The modulus must be between 1 and %d, but %d was provided.", MAX_MODULUS, *modulus)
+	}
+	if *multiplier < 0 {
+		log.Fatalf("The multiplier cannot be negative, but %d was provided.", *multiplier)
+	}
+	hashFunc := hashFunction{multiplier: *multiplier, modulus: *modulus}
+
 	fileLines := utils.LoadFile("input.txt")
 	if len(fileLines) != 1 {
 		panic("Unexpected input file format.")
@@ -155,7 +341,16 @@ func main() {
 
 	stringSequence := strings.Split(fileLines[0], ",")
 	initializationSequence := parseOperations(stringSequence)
-	sum := run(initializationSequence)
+	var traceWriter io.Writer
+	if *trace {
+		traceWriter = os.Stdout
+	}
+	hashMap := run(initializationSequence, hashFunc, traceWriter)
+
+	if *showStats {
+		fmt.Print(computeStatistics(hashMap, initializationSequence))
+		fmt.Println()
+	}
 
-	fmt.Printf("The total focusing power is %d.\n", sum)
+	fmt.Printf("The total focusing power is %d.\n", hashMap.focusingPower())
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	utils "kqarryzada/advent-of-code-2023/utils"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func Test_hash(t *testing.T) {
+	tests := []struct {
+		name     string
+		hashFunc hashFunction
+		sequence string
+		want     int
+	}{
+		{"HASH", DEFAULT_HASH, "HASH", 52},
+		{"rn=1", DEFAULT_HASH, "rn=1", 30},
+		{"cm-", DEFAULT_HASH, "cm-", 253},
+		{"Label rn", DEFAULT_HASH, "rn", 0},
+		{"Label qp", DEFAULT_HASH, "qp", 1},
+		{"Label pc", DEFAULT_HASH, "pc", 3},
+		{"Equivalent multiplier", hashFunction{multiplier: 17 + 256*1000, modulus: 256}, "HASH", 52},
+		{"Large multiplier", hashFunction{multiplier: 1<<62 + 17, modulus: 256}, "HASH", 52},
+		{"Largest modulus", hashFunction{multiplier: 1<<63 - 1, modulus: MAX_MODULUS}, "HASH", 18},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hashFunc.hash(tt.sequence); got != tt.want {
+				t.Errorf("hash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_lensHashMap(t *testing.T) {
+	// The labels "rn" and "cm" both hash to box 0.
+	tests := []struct {
+		name       string
+		operations []string
+		want       string
+	}{
+		{"Insertion order", []string{"rn=1", "cm=2"}, "Box 0: [rn 1] [cm 2]\n"},
+		{"Replace keeps the position", []string{"rn=1", "cm=2", "rn=5"}, "Box 0: [rn 5] [cm 2]\n"},
+		{"Remove the first lens", []string{"rn=1", "cm=2", "rn-"}, "Box 0: [cm 2]\n"},
+		{"Remove the last lens", []string{"rn=1", "cm=2", "cm-"}, "Box 0: [rn 1]\n"},
+		{"Reinsert at the back", []string{"rn=1", "cm=2", "rn-", "rn=3"}, "Box 0: [cm 2] [rn 3]\n"},
+		{"Remove a missing label", []string{"rn=1", "cm-"}, "Box 0: [rn 1]\n"},
+		{"Empty box", []string{"rn=1", "rn-"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hashMap := run(parseOperations(tt.operations), DEFAULT_HASH, nil)
+			if got := hashMap.String(); got != tt.want {
+				t.Errorf("lensHashMap = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// The walkthrough of the example in the puzzle description.
+var exampleTrace = `After "rn=1":
+Box 0: [rn 1]
+
+After "cm-":
+Box 0: [rn 1]
+
+After "qp=3":
+Box 0: [rn 1]
+Box 1: [qp 3]
+
+After "cm=2":
+Box 0: [rn 1] [cm 2]
+Box 1: [qp 3]
+
+After "qp-":
+Box 0: [rn 1] [cm 2]
+
+After "pc=4":
+Box 0: [rn 1] [cm 2]
+Box 3: [pc 4]
+
+After "ot=9":
+Box 0: [rn 1] [cm 2]
+Box 3: [pc 4] [ot 9]
+
+After "ab=5":
+Box 0: [rn 1] [cm 2]
+Box 3: [pc 4] [ot 9] [ab 5]
+
+After "pc-":
+Box 0: [rn 1] [cm 2]
+Box 3: [ot 9] [ab 5]
+
+After "pc=6":
+Box 0: [rn 1] [cm 2]
+Box 3: [ot 9] [ab 5] [pc 6]
+
+After "ot=7":
+Box 0: [rn 1] [cm 2]
+Box 3: [ot 7] [ab 5] [pc 6]
+
+`
+
+func Test_run(t *testing.T) {
+	fileLines := utils.LoadFile("example.txt")
+	operations := parseOperations(strings.Split(fileLines[0], ","))
+
+	var trace bytes.Buffer
+	hashMap := run(operations, DEFAULT_HASH, &trace)
+	if got := trace.String(); got != exampleTrace {
+		t.Errorf("run() trace = %q, want %q", got, exampleTrace)
+	}
+	if got := hashMap.focusingPower(); got != 145 {
+		t.Errorf("focusingPower() = %v, want 145", got)
+	}
+}
+
+// Test_computeStatistics checks the statistics of the example. The counts of
+// labels, boxes used, and collisions include every label that was ever seen,
+// the longest chain is the largest size a box reached, and the occupancy
+// describes the boxes once the sequence is complete.
+func Test_computeStatistics(t *testing.T) {
+	fileLines := utils.LoadFile("example.txt")
+	operations := parseOperations(strings.Split(fileLines[0], ","))
+	stats := computeStatistics(run(operations, DEFAULT_HASH, nil), operations)
+
+	// "rn" and "cm" share box 0, while "pc", "ot", and "ab" share box 3. The
+	// label "qp" is removed, but still counts towards the boxes used.
+	if stats.labelCount != 6 {
+		t.Errorf("labelCount = %v, want 6", stats.labelCount)
+	}
+	if stats.boxesUsed != 3 {
+		t.Errorf("boxesUsed = %v, want 3", stats.boxesUsed)
+	}
+	if stats.boxCount != 256 {
+		t.Errorf("boxCount = %v, want 256", stats.boxCount)
+	}
+	if stats.collisions != 3 {
+		t.Errorf("collisions = %v, want 3", stats.collisions)
+	}
+	if stats.longestChain != 3 {
+		t.Errorf("longestChain = %v, want 3", stats.longestChain)
+	}
+
+	wantOccupancy := map[int]int{0: 254, 2: 1, 3: 1}
+	if !maps.Equal(stats.occupancy, wantOccupancy) {
+		t.Errorf("occupancy = %v, want %v", stats.occupancy, wantOccupancy)
+	}
+}
+
+// Test_computeStatistics_peak checks that the longest chain is the peak size of
+// a box, even after lenses have been removed from it.
+func Test_computeStatistics_peak(t *testing.T) {
+	operations := parseOperations([]string{"rn=1", "cm=2", "rn-", "cm-"})
+	stats := computeStatistics(run(operations, DEFAULT_HASH, nil), operations)
+
+	if stats.longestChain != 2 {
+		t.Errorf("longestChain = %v, want 2", stats.longestChain)
+	}
+	if stats.collisions != 1 {
+		t.Errorf("collisions = %v, want 1", stats.collisions)
+	}
+	if !maps.Equal(stats.occupancy, map[int]int{0: 256}) {
+		t.Errorf("occupancy = %v, want every box empty", stats.occupancy)
+	}
+}
@@ -1,8 +1,17 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
 	utils "kqarryzada/advent-of-code-2023/utils"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
 var NUM_ROWS int
@@ -240,34 +249,223 @@ func sumEnergizedTiles(grid [][]*tile) int {
 	return sum
 }
 
+// tileCharacter returns the character that represents the tile type in the
+// puzzle input.
+func tileCharacter(t tileType) rune {
+	switch t {
+	case EMPTY_SPACE:
+		return '.'
+	case MIRROR_FORWARD_SLASH:
+		return '/'
+	case MIRROR_BACKSLASH:
+		return '\\'
+	case SPLIT_HORIZTONAL:
+		return '-'
+	case SPLIT_VERTICAL:
+		return '|'
+	default:
+		panic("Invalid tile type found.")
+	}
+}
+
+// beamCharacter returns the character used to display the beams that passed
+// through an empty tile. A single beam is shown as an arrow, while a tile with
+// beams in several directions shows the number of distinct directions, which
+// matches the diagrams in the puzzle description.
+func beamCharacter(t *tile) rune {
+	directions := make([]rune, 0)
+	if t.hasUpTravelled {
+		directions = append(directions, '^')
+	}
+	if t.hasDownTravelled {
+		directions = append(directions, 'v')
+	}
+	if t.hasLeftTravelled {
+		directions = append(directions, '<')
+	}
+	if t.hasRightTravelled {
+		directions = append(directions, '>')
+	}
+
+	switch len(directions) {
+	case 0:
+		return '.'
+	case 1:
+		return directions[0]
+	default:
+		return rune('0' + len(directions))
+	}
+}
+
+// renderGrid returns the grid as a string after light has travelled through
+// it. When 'showBeams' is false, energized tiles are displayed as '#' and all
+// other tiles are displayed as '.'. When 'showBeams' is true, the original
+// contraption is displayed with the beam directions drawn on the empty tiles.
+func renderGrid(grid [][]*tile, showBeams bool) string {
+	var builder strings.Builder
+	for _, gridLine := range grid {
+		for _, gridTile := range gridLine {
+			var char rune
+			if !showBeams {
+				char = '.'
+				if gridTile.isEnergized {
+					char = '#'
+				}
+			} else if gridTile.Type == EMPTY_SPACE {
+				char = beamCharacter(gridTile)
+			} else {
+				char = tileCharacter(gridTile.Type)
+			}
+
+			builder.WriteRune(char)
+		}
+		builder.WriteRune('\n')
+	}
+
+	return builder.String()
+}
+
+// The colors used when exporting the grid as an image.
+var (
+	EMPTY_COLOR     = color.RGBA{R: 20, G: 20, B: 30, A: 255}
+	ENERGIZED_COLOR = color.RGBA{R: 255, G: 170, B: 0, A: 255}
+	MIRROR_COLOR    = color.RGBA{R: 120, G: 180, B: 255, A: 255}
+	SPLITTER_COLOR  = color.RGBA{R: 200, G: 80, B: 200, A: 255}
+)
+
+// tileColor determines the color of a tile in an exported image. Mirrors and
+// splitters are always drawn in their own color so that the layout of the
+// contraption remains visible.
+func tileColor(t *tile) color.RGBA {
+	switch t.Type {
+	case MIRROR_FORWARD_SLASH, MIRROR_BACKSLASH:
+		return MIRROR_COLOR
+	case SPLIT_HORIZTONAL, SPLIT_VERTICAL:
+		return SPLITTER_COLOR
+	}
+
+	if t.isEnergized {
+		return ENERGIZED_COLOR
+	}
+	return EMPTY_COLOR
+}
+
+// gridToImage converts the grid into an image, where each tile is drawn as a
+// square with a side length of 'scale' pixels.
+func gridToImage(grid [][]*tile, scale int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, NUM_COLS*scale, NUM_ROWS*scale))
+	for i, gridLine := range grid {
+		for j, gridTile := range gridLine {
+			tileColor := tileColor(gridTile)
+			for y := i * scale; y < (i+1)*scale; y++ {
+				for x := j * scale; x < (j+1)*scale; x++ {
+					img.SetRGBA(x, y, tileColor)
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+// writePPM encodes an image in the binary PPM (P6) format.
+func writePPM(output io.Writer, img *image.RGBA) error {
+	writer := bufio.NewWriter(output)
+	bounds := img.Bounds()
+	fmt.Fprintf(writer, "P6\n%d %d\n255\n", bounds.Dx(), bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pixel := img.RGBAAt(x, y)
+			writer.Write([]byte{pixel.R, pixel.G, pixel.B})
+		}
+	}
+
+	return writer.Flush()
+}
+
+// exportImage saves the grid as an image. The format is determined by the file
+// extension, which must be either '.png' or '.ppm'.
+func exportImage(grid [][]*tile, filename string, scale int) error {
+	if scale < 1 {
+		return fmt.Errorf("the image scale must be at least 1, but %d was provided", scale)
+	}
+
+	extension := strings.ToLower(filepath.Ext(filename))
+	if extension != ".png" && extension != ".ppm" {
+		return fmt.Errorf("unsupported image format '%s', use '.png' or '.ppm'", extension)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	img := gridToImage(grid, scale)
+	if extension == ".ppm" {
+		return writePPM(file, img)
+	}
+	return png.Encode(file, img)
+}
+
 func main() {
+	render := flag.String("render", "", "Print the grid of the best configuration. Use 'energized' to display energized tiles, or 'beams' to display the beam directions.")
+	imageFile := flag.String("image", "", "Export the grid of the best configuration as a '.png' or '.ppm' image.")
+	scale := flag.Int("scale", 4, "The side length, in pixels, of each tile in an exported image.")
+	flag.Parse()
+
+	if *render != "" && *render != "energized" && *render != "beams" {
+		fmt.Fprintf(os.Stderr, "Invalid render mode '%s'. Use 'energized' or 'beams'.\n", *render)
+		os.Exit(1)
+	}
+
 	fileLines := utils.LoadFile("input.txt")
 	grid := parse(fileLines)
 
 	maxValue := 0
-	for i := range grid {
-		followPath(i, 0, grid, RIGHT)
-		rightValue := sumEnergizedTiles(grid)
-		maxValue = max(maxValue, rightValue)
+	bestRow, bestCol, bestDir := 0, 0, RIGHT
+
+	// tryPath energizes the grid from a single edge source and records the
+	// source if it produced the largest number of energized tiles so far.
+	tryPath := func(row int, col int, dir direction) {
+		followPath(row, col, grid, dir)
+		value := sumEnergizedTiles(grid)
+		if value > maxValue {
+			maxValue = value
+			bestRow, bestCol, bestDir = row, col, dir
+		}
 		clearGrid(grid)
+	}
 
-		followPath(i, NUM_COLS-1, grid, LEFT)
-		leftValue := sumEnergizedTiles(grid)
-		maxValue = max(maxValue, leftValue)
-		clearGrid(grid)
+	for i := range grid {
+		tryPath(i, 0, RIGHT)
+		tryPath(i, NUM_COLS-1, LEFT)
 	}
 
 	for j := 0; j < NUM_COLS; j++ {
-		followPath(0, j, grid, DOWN)
-		downValue := sumEnergizedTiles(grid)
-		maxValue = max(maxValue, downValue)
-		clearGrid(grid)
-
-		followPath(NUM_ROWS-1, j, grid, LEFT)
-		leftValue := sumEnergizedTiles(grid)
-		maxValue = max(maxValue, leftValue)
-		clearGrid(grid)
+		tryPath(0, j, DOWN)
+		tryPath(NUM_ROWS-1, j, UP)
 	}
 
 	fmt.Printf("The maximum number of energized tiles from an edge source is %d.\n", maxValue)
+
+	if *render == "" && *imageFile == "" {
+		return
+	}
+
+	// Re-run the best configuration so that the grid reflects its final state.
+	followPath(bestRow, bestCol, grid, bestDir)
+
+	if *render != "" {
+		fmt.Println()
+		fmt.Print(renderGrid(grid, *render == "beams"))
+	}
+
+	if *imageFile != "" {
+		if err := exportImage(grid, *imageFile, *scale); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to export the image: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("The grid was exported to %s.\n", *imageFile)
+	}
 }
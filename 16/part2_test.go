@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	utils "kqarryzada/advent-of-code-2023/utils"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The energized tiles of the example, as shown in the puzzle description.
+var exampleEnergized = `######....
+.#...#....
+.#...#####
+.#...##...
+.#...##...
+.#...##...
+.#..####..
+########..
+.#######..
+.#...#.#..
+`
+
+// The beams of the example, as shown in the puzzle description.
+var exampleBeams = `>|<<<\....
+|v-.\^....
+.v...|->>>
+.v...v^.|.
+.v...v^...
+.v...v^..\
+.v../2\\..
+<->-/vv|..
+.|<<<2-|.\
+.v//.|.v..
+`
+
+// energizedExample follows the beam of part 1 through the example.
+func energizedExample() [][]*tile {
+	grid := parse(utils.LoadFile("example.txt"))
+	followPath(0, 0, grid, RIGHT)
+	return grid
+}
+
+func Test_renderGrid(t *testing.T) {
+	grid := energizedExample()
+	if got := sumEnergizedTiles(grid); got != 46 {
+		t.Errorf("sumEnergizedTiles() = %v, want 46", got)
+	}
+
+	tests := []struct {
+		name      string
+		showBeams bool
+		want      string
+	}{
+		{"Energized tiles", false, exampleEnergized},
+		{"Beams", true, exampleBeams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderGrid(grid, tt.showBeams); got != tt.want {
+				t.Errorf("renderGrid() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if got := strings.Count(exampleEnergized, "#"); got != 46 {
+		t.Errorf("the expected diagram has %d energized tiles, want 46", got)
+	}
+}
+
+func Test_writePPM(t *testing.T) {
+	grid := energizedExample()
+	const scale = 3
+
+	var buffer bytes.Buffer
+	if err := writePPM(&buffer, gridToImage(grid, scale)); err != nil {
+		t.Fatal(err)
+	}
+
+	width, height := NUM_COLS*scale, NUM_ROWS*scale
+	header := fmt.Sprintf("P6\n%d %d\n255\n", width, height)
+	if !strings.HasPrefix(buffer.String(), header) {
+		t.Fatalf("writePPM() does not begin with the header %q", header)
+	}
+	if got, want := buffer.Len(), len(header)+width*height*3; got != want {
+		t.Errorf("writePPM() wrote %d bytes, want %d", got, want)
+	}
+
+	// The top-left tile is energized, so its first pixel follows the header.
+	pixel := buffer.Bytes()[len(header) : len(header)+3]
+	if pixel[0] != ENERGIZED_COLOR.R || pixel[1] != ENERGIZED_COLOR.G || pixel[2] != ENERGIZED_COLOR.B {
+		t.Errorf("the first pixel is %v, want the energized color", pixel)
+	}
+}
+
+func Test_exportImage(t *testing.T) {
+	grid := energizedExample()
+	directory := t.TempDir()
+
+	tests := []struct {
+		name     string
+		filename string
+		scale    int
+		wantErr  bool
+	}{
+		{"PNG", "grid.png", 2, false},
+		{"Uppercase PPM", "grid.PPM", 1, false},
+		{"Unknown extension", "grid.gif", 2, true},
+		{"No extension", "grid", 2, true},
+		{"Scale of zero", "zero.png", 0, true},
+		{"Negative scale", "negative.ppm", -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(directory, tt.filename)
+			err := exportImage(grid, filename, tt.scale)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("exportImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if _, statErr := os.Stat(filename); statErr == nil {
+					t.Errorf("exportImage() created %s despite the error", tt.filename)
+				}
+			}
+		})
+	}
+
+	file, err := os.Open(filepath.Join(directory, "grid.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("the exported PNG is invalid: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != NUM_COLS*2 || bounds.Dy() != NUM_ROWS*2 {
+		t.Errorf("the exported PNG is %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), NUM_COLS*2, NUM_ROWS*2)
+	}
+}
@@ -0,0 +1,58 @@
+package utils
+
+// Cycle describes a sequence of states produced by repeatedly applying a step
+// function to an initial state, where the sequence eventually begins to repeat.
+type Cycle[S any] struct {
+	// The number of steps performed before the repeating sequence begins.
+	Start int
+
+	// The number of steps within the repeating sequence.
+	Length int
+
+	// Every distinct state that was seen, in the order in which they occurred.
+	// This contains exactly Start + Length values.
+	states []S
+}
+
+// FindCycle iterates the step function from the initial state until a state is
+// repeated. States are compared with the provided key function, which must
+// return the same key for two states if and only if they are identical.
+//
+// The step function should return a new state rather than modifying its input,
+// since each state is retained so that StateAt does not need to iterate again.
+func FindCycle[S any, K comparable](initial S, step func(S) S, key func(S) K) *Cycle[S] {
+	firstSeen := make(map[K]int)
+	states := make([]S, 0)
+
+	state := initial
+	for index := 0; ; index++ {
+		stateKey := key(state)
+		if seenIndex, ok := firstSeen[stateKey]; ok {
+			return &Cycle[S]{Start: seenIndex, Length: index - seenIndex, states: states}
+		}
+
+		firstSeen[stateKey] = index
+		states = append(states, state)
+		state = step(state)
+	}
+}
+
+// StateAt returns the state after 'n' steps have been applied to the initial
+// state. Values of 'n' beyond the start of the cycle are extrapolated.
+func (c *Cycle[S]) StateAt(n int) S {
+	if n < 0 {
+		panic("The number of steps cannot be negative.")
+	}
+
+	return c.states[c.IndexOf(n)]
+}
+
+// IndexOf reduces a step count to the smallest equivalent step count. The state
+// after 'n' steps is identical to the state after IndexOf(n) steps.
+func (c *Cycle[S]) IndexOf(n int) int {
+	if n < c.Start {
+		return n
+	}
+
+	return c.Start + (n-c.Start)%c.Length
+}
@@ -0,0 +1,58 @@
+package utils
+
+import "testing"
+
+func TestFindCycle(t *testing.T) {
+	type args struct {
+		initial int
+		step    func(int) int
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantStart  int
+		wantLength int
+	}{
+		{"Immediate fixed point", args{0, func(n int) int { return 0 }}, 0, 1},
+		{"Loop from the start", args{0, func(n int) int { return (n + 1) % 4 }}, 0, 4},
+		{"Prefix before the loop", args{0, func(n int) int { return min(n+1, 5) }}, 5, 1},
+		{"Prefix and loop", args{0, func(n int) int {
+			if n < 3 {
+				return n + 1
+			}
+			return 3 + (n-2)%4
+		}}, 3, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity := func(n int) int { return n }
+			got := FindCycle(tt.args.initial, tt.args.step, identity)
+			if got.Start != tt.wantStart || got.Length != tt.wantLength {
+				t.Errorf("FindCycle() = (%v, %v), want (%v, %v)", got.Start, got.Length, tt.wantStart, tt.wantLength)
+			}
+		})
+	}
+}
+
+func TestCycle_StateAt(t *testing.T) {
+	step := func(n int) int {
+		if n < 3 {
+			return n + 1
+		}
+		return 3 + (n-2)%4
+	}
+	identity := func(n int) int { return n }
+	cycle := FindCycle(0, step, identity)
+
+	state := 0
+	for n := 0; n < 50; n++ {
+		if got := cycle.StateAt(n); got != state {
+			t.Errorf("StateAt(%d) = %v, want %v", n, got, state)
+		}
+		state = step(state)
+	}
+
+	if got := cycle.StateAt(1_000_000_000); got != 4 {
+		t.Errorf("StateAt(1_000_000_000) = %v, want %v", got, 4)
+	}
+}